@@ -0,0 +1,185 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/product"
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/quota"
+	"github.com/PNAP/go-sdk-helper-bmc/dto"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// serverQuotaID identifies the quota limiting the number of servers of an account.
+const serverQuotaID = "bmc.servers.max_count"
+
+// Values of availability_check_mode.
+const (
+	availabilityCheckFail = "fail"
+	availabilityCheckWarn = "warn"
+)
+
+// availabilityCheck counts the servers planned per type and location while the provider is configured,
+// so that servers sharing a type and location are checked against the stock together. Terraform configures
+// the provider anew for every walk. The plan walk plans each resource instance once, and the apply walk
+// plans each instance again right before applying it, when the servers created earlier in the walk already
+// take up stock and quota. The stock and the quota are therefore fetched once per configuration, before any
+// server of the walk is created, and every planned server is counted against that snapshot.
+type availabilityCheck struct {
+	// warnOnly logs a shortfall instead of failing the plan
+	warnOnly bool
+
+	mu sync.Mutex
+	// planned counts the new servers per "<type>/<location>"
+	planned map[string]int
+	// stock holds the available quantity per server type and location
+	stock map[string]map[string]int
+	// quota is the server quota of the account, nil until it has been fetched
+	quota *serverQuota
+}
+
+// serverQuota is the part of the server quota the check needs.
+type serverQuota struct {
+	name  string
+	used  int
+	limit int
+	// found is false if the account has no server quota
+	found bool
+}
+
+func newAvailabilityCheck(mode string) *availabilityCheck {
+	return &availabilityCheck{
+		warnOnly: mode == availabilityCheckWarn,
+		planned:  make(map[string]int),
+		stock:    make(map[string]map[string]int),
+	}
+}
+
+// plan records a new server and returns the number of new servers planned for its type and location,
+// along with the number of new servers planned overall.
+func (a *availabilityCheck) plan(serverType, location string) (int, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.planned[serverType+"/"+location]++
+	total := 0
+	for _, count := range a.planned {
+		total += count
+	}
+	return a.planned[serverType+"/"+location], total
+}
+
+// stockOf returns the available quantity of a server type per location, fetched on first use.
+func (a *availabilityCheck) stockOf(ctx context.Context, meta *providerMeta, serverType string) (map[string]int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if stock, ok := a.stock[serverType]; ok {
+		return stock, nil
+	}
+	query := dto.ProductAvailabilityQuery{}
+	query.ProductCategory = []string{"SERVER"}
+	query.ProductCode = []string{serverType}
+	query.ShowOnlyMinQuantityAvailable = false
+	requestCommand := product.NewGetProductAvailabilityCommand(meta.client, query)
+	resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return nil, err
+	}
+	stock := make(map[string]int)
+	for _, productAvailability := range resp {
+		if productAvailability.ProductCode != serverType {
+			continue
+		}
+		for _, l := range productAvailability.LocationAvailabilityDetails {
+			stock[string(l.Location)] = int(l.AvailableQuantity)
+		}
+	}
+	a.stock[serverType] = stock
+	return stock, nil
+}
+
+// serverQuota returns the server quota of the account, fetched on first use.
+func (a *availabilityCheck) serverQuota(ctx context.Context, meta *providerMeta) (*serverQuota, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.quota != nil {
+		return a.quota, nil
+	}
+	quotasCommand := quota.NewGetQuotasCommand(meta.client)
+	quotas, err := execute(ctx, meta, retryIdempotent, quotasCommand.Execute)
+	if err != nil {
+		return nil, err
+	}
+	a.quota = &serverQuota{}
+	for _, q := range quotas {
+		if q.Id == serverQuotaID {
+			a.quota = &serverQuota{name: q.Name, used: int(q.Used), limit: int(q.Limit), found: true}
+		}
+	}
+	return a.quota, nil
+}
+
+// shortfall fails the plan with err, or only logs it in the warn mode. SDK v2 can't attach warnings to a plan.
+func (a *availabilityCheck) shortfall(err error) error {
+	if !a.warnOnly {
+		return err
+	}
+	log.Printf("[WARN] %s", err)
+	return nil
+}
+
+// resourceServerAvailabilityDiff fails the plan of a new server if its type is out of stock in its location
+// or the server quota doesn't leave room for it. It only runs with check_availability set on the provider, and
+// only logs a warning when availability_check_mode is "warn".
+func resourceServerAvailabilityDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	meta := m.(*providerMeta)
+	if meta.availability == nil {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChange("type") && !d.HasChange("location") {
+		return nil
+	}
+	if !d.NewValueKnown("type") || !d.NewValueKnown("location") {
+		return nil
+	}
+	serverType := d.Get("type").(string)
+	location := d.Get("location").(string)
+	needed, total := meta.availability.plan(serverType, location)
+
+	// The quota is fetched along with the first stock, before any server of the walk is created.
+	q, err := meta.availability.serverQuota(ctx, meta)
+	if err != nil {
+		return fmt.Errorf("checking the server quota: %w", err)
+	}
+	stock, err := meta.availability.stockOf(ctx, meta, serverType)
+	if err != nil {
+		return fmt.Errorf("checking the availability of %s: %w", serverType, err)
+	}
+	available := stock[location]
+	var alternatives []string
+	for l, quantity := range stock {
+		if l != location && quantity >= needed {
+			alternatives = append(alternatives, fmt.Sprintf("%s (%d)", l, quantity))
+		}
+	}
+	if available < needed {
+		sort.Strings(alternatives)
+		alternativesText := "no other location has enough stock"
+		if len(alternatives) > 0 {
+			alternativesText = "available in " + strings.Join(alternatives, ", ")
+		}
+		return meta.availability.shortfall(fmt.Errorf("type: %d %s server(s) planned in %s but only %d available, %s", needed, serverType, location, available, alternativesText))
+	}
+
+	// A replaced server is deleted before its replacement is created, it doesn't take up more of the quota.
+	if d.Id() != "" {
+		return nil
+	}
+	if q.found && q.used+total > q.limit {
+		return meta.availability.shortfall(fmt.Errorf("%d new server(s) planned but the %s quota has %d of %d in use, request a higher limit with pnap_quota_edit_request", total, q.name, q.used, q.limit))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package pnap
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultTokenURL   = "https://auth.phoenixnap.com/auth/realms/BMC/protocol/openid-connect/token"
+	defaultApiBaseURL = "https://api.phoenixnap.com/"
+	configFileName    = "config.yaml"
+)
+
+// credentialProfile is a named section of the configuration file, or its top level holding the default
+// credentials.
+type credentialProfile struct {
+	ClientID     string `yaml:"clientId"`
+	ClientSecret string `yaml:"clientSecret"`
+	TokenURL     string `yaml:"tokenUrl"`
+	ApiBaseURL   string `yaml:"apiBaseUrl"`
+}
+
+// defaultConfigFilePath returns the location of the configuration file the SDK helper reads by default.
+func defaultConfigFilePath() (string, error) {
+	if runtime.GOOS == "windows" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "pnap", configFileName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".pnap", configFileName), nil
+}
+
+// resolveConfigFilePath returns the configuration file to read profiles from, config_file_path may name
+// the file or the directory holding it.
+func resolveConfigFilePath(path string) (string, error) {
+	if path == "" {
+		return defaultConfigFilePath()
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return filepath.Join(path, configFileName), nil
+	}
+	return path, nil
+}
+
+// loadProfile reads the named section of a configuration file such as:
+//
+//	clientId: <default client id>
+//	clientSecret: <default client secret>
+//	staging:
+//	  clientId: <staging client id>
+//	  clientSecret: <staging client secret>
+//	  tokenUrl: <optional token URL>
+//	  apiBaseUrl: <optional API base URL>
+func loadProfile(path, name string) (credentialProfile, error) {
+	profile := credentialProfile{}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return profile, err
+	}
+	sections := map[string]yaml.Node{}
+	if err := yaml.Unmarshal(content, &sections); err != nil {
+		return profile, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	section, ok := sections[name]
+	if !ok || section.Kind != yaml.MappingNode {
+		var names []string
+		for k, v := range sections {
+			if v.Kind == yaml.MappingNode {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return profile, fmt.Errorf("profile %q not found in %s, the file doesn't define any profile", name, path)
+		}
+		return profile, fmt.Errorf("profile %q not found in %s, available profiles: %s", name, path, strings.Join(names, ", "))
+	}
+	if err := section.Decode(&profile); err != nil {
+		return profile, fmt.Errorf("parsing profile %q in %s: %w", name, path, err)
+	}
+	if profile.ClientID == "" || profile.ClientSecret == "" {
+		return profile, fmt.Errorf("profile %q in %s must set both clientId and clientSecret", name, path)
+	}
+	return profile, nil
+}
+
+// loadDefaultCredentials reads the credentials at the top level of a configuration file, outside of any
+// profile.
+func loadDefaultCredentials(path string) (credentialProfile, error) {
+	credentials := credentialProfile{}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return credentials, err
+	}
+	if err := yaml.Unmarshal(content, &credentials); err != nil {
+		return credentials, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if credentials.ClientID == "" || credentials.ClientSecret == "" {
+		return credentials, fmt.Errorf("%s must set both clientId and clientSecret", path)
+	}
+	return credentials, nil
+}
+
+// credentialSettings are the credential arguments of the provider, taken either from the provider block or
+// from the environment.
+type credentialSettings struct {
+	accessToken  string
+	tokenCommand string
+	clientID     string
+	clientSecret string
+	profile      string
+}
+
+// isSet reports whether any credential argument is set.
+func (s credentialSettings) isSet() bool {
+	return s.accessToken != "" || s.tokenCommand != "" || s.clientID != "" || s.clientSecret != "" || s.profile != ""
+}
+
+// credentialSettingsFromEnv returns the credential arguments set in the environment, token_command has no
+// environment variable.
+func credentialSettingsFromEnv() credentialSettings {
+	return credentialSettings{
+		accessToken:  os.Getenv("PNAP_ACCESS_TOKEN"),
+		clientID:     os.Getenv("PNAP_CLIENT_ID"),
+		clientSecret: os.Getenv("PNAP_CLIENT_SECRET"),
+		profile:      os.Getenv("PNAP_PROFILE"),
+	}
+}
+
+// firstNonEmpty returns the first of the values that is set.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// withCredentialSource names the credential source in authentication failures, so that a rejected
+// secret can be traced back to the profile, file or arguments it came from.
+func withCredentialSource(err error, source string) error {
+	if err == nil || source == "" {
+		return err
+	}
+	if statusCode(err) != http.StatusUnauthorized && !strings.Contains(err.Error(), "oauth2:") {
+		return err
+	}
+	return fmt.Errorf("%w (credentials from %s)", err, source)
+}
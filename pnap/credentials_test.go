@@ -0,0 +1,224 @@
+package pnap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapProvider_profile(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	configFile := testUnitConfigFile(t, fmt.Sprintf(`
+clientId: default-client-id
+clientSecret: default-client-secret
+staging:
+  clientId: %s
+  clientSecret: %s
+  tokenUrl: %s/auth/realms/BMC/protocol/openid-connect/token
+  apiBaseUrl: %s/
+revoked:
+  clientId: %s
+  clientSecret: revoked-secret
+  tokenUrl: %s/auth/realms/BMC/protocol/openid-connect/token
+  apiBaseUrl: %s/
+`, mockClientID, mockClientSecret, api.URL, api.URL, mockClientID, api.URL, api.URL))
+	providers := fmt.Sprintf(`
+provider "pnap" {
+	profile = "staging"
+	config_file_path = "%s"
+	poll_interval = 1
+}
+
+provider "pnap" {
+	alias = "revoked"
+	profile = "revoked"
+	config_file_path = "%s"
+}
+`, configFile, configFile)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ssh_key"),
+		Steps: []resource.TestStep{
+			{
+				Config: providers + testUnitSshKeyResource(rName, rName, false),
+				Check:  resource.TestCheckResourceAttr("pnap_ssh_key."+rName, "name", rName),
+			},
+			{
+				Config: providers + fmt.Sprintf(`
+resource "pnap_ssh_key" "revoked" {
+	provider = pnap.revoked
+	name = "%s-revoked"
+	default = false
+	key = "%s"
+}`, rName, testUnitSshKey),
+				ExpectError: regexp.MustCompile(`credentials from profile "revoked"`),
+			},
+		},
+	})
+}
+
+func TestUnitPnapProvider_credentialPrecedence(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	configFile := testUnitConfigFile(t, fmt.Sprintf(`
+clientId: %s
+clientSecret: revoked-secret
+tokenUrl: %s/auth/realms/BMC/protocol/openid-connect/token
+apiBaseUrl: %s/
+staging:
+  clientId: %s
+  clientSecret: %s
+  tokenUrl: %s/auth/realms/BMC/protocol/openid-connect/token
+  apiBaseUrl: %s/
+revoked:
+  clientId: %s
+  clientSecret: revoked-secret
+  tokenUrl: %s/auth/realms/BMC/protocol/openid-connect/token
+  apiBaseUrl: %s/
+`, mockClientID, api.URL, api.URL, mockClientID, mockClientSecret, api.URL, api.URL, mockClientID, api.URL, api.URL))
+	profileProvider := fmt.Sprintf(`
+provider "pnap" {
+	profile = "staging"
+	config_file_path = "%s"
+	poll_interval = 1
+}
+`, configFile)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ssh_key"),
+		Steps: []resource.TestStep{
+			{
+				// client_id and client_secret win over the profile
+				Config: testUnitProviderConfigWith(api, fmt.Sprintf("\tprofile = \"revoked\"\n\tconfig_file_path = \"%s\"\n", configFile)) +
+					testUnitSshKeyResource(rName, rName, false),
+				Check: resource.TestCheckResourceAttr("pnap_ssh_key."+rName, "name", rName),
+			},
+			{
+				// the profile wins over the default credentials of the file
+				Config:   profileProvider + testUnitSshKeyResource(rName, rName, false),
+				PlanOnly: true,
+			},
+			{
+				Config: profileProvider + fmt.Sprintf(`
+provider "pnap" {
+	alias = "file"
+	config_file_path = "%s"
+}
+
+resource "pnap_ssh_key" "file" {
+	provider = pnap.file
+	name = "%s-file"
+	default = false
+	key = "%s"
+}`, configFile, rName, testUnitSshKey) + testUnitSshKeyResource(rName, rName, false),
+				ExpectError: regexp.MustCompile(`credentials from config file`),
+			},
+		},
+	})
+}
+
+func TestUnitPnapProvider_profileOverEnvCredentials(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	configFile := testUnitConfigFile(t, fmt.Sprintf(`
+staging:
+  clientId: %s
+  clientSecret: %s
+  tokenUrl: %s/auth/realms/BMC/protocol/openid-connect/token
+  apiBaseUrl: %s/
+`, mockClientID, mockClientSecret, api.URL, api.URL))
+	t.Setenv("PNAP_CLIENT_ID", mockClientID)
+	t.Setenv("PNAP_CLIENT_SECRET", "revoked-secret")
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ssh_key"),
+		Steps: []resource.TestStep{
+			{
+				// the profile of the provider block wins over the client credentials of the environment
+				Config: fmt.Sprintf(`
+provider "pnap" {
+	profile = "staging"
+	config_file_path = "%s"
+	poll_interval = 1
+}
+`, configFile) + testUnitSshKeyResource(rName, rName, false),
+				Check: resource.TestCheckResourceAttr("pnap_ssh_key."+rName, "name", rName),
+			},
+		},
+	})
+}
+
+func TestUnitPnapProvider_missingProfile(t *testing.T) {
+	configFile := testUnitConfigFile(t, "staging:\n  clientId: id\n  clientSecret: secret\n")
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "pnap" {
+	profile = "production"
+	config_file_path = "%s"
+}
+`, configFile) + testUnitSshKeyResource("missing", "missing", false),
+				ExpectError: regexp.MustCompile(`profile "production" not found in .*, available profiles: staging`),
+			},
+		},
+	})
+}
+
+func TestLoadProfile(t *testing.T) {
+	configFile := testUnitConfigFile(t, `
+clientId: default-id
+clientSecret: default-secret
+staging:
+  clientId: staging-id
+  clientSecret: staging-secret
+  apiBaseUrl: https://staging.example.com/
+incomplete:
+  clientId: incomplete-id
+`)
+	profile, err := loadProfile(configFile, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := credentialProfile{ClientID: "staging-id", ClientSecret: "staging-secret", ApiBaseURL: "https://staging.example.com/"}
+	if profile != expected {
+		t.Errorf("loadProfile() = %+v, expected %+v", profile, expected)
+	}
+	for name, message := range map[string]string{
+		"incomplete": "must set both clientId and clientSecret",
+		"clientId":   "available profiles: incomplete, staging",
+		"production": "available profiles: incomplete, staging",
+	} {
+		if _, err := loadProfile(configFile, name); err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("loadProfile(%q) returned %v, expected an error containing %q", name, err, message)
+		}
+	}
+}
+
+func TestResolveConfigFilePath(t *testing.T) {
+	dir := t.TempDir()
+	if path, err := resolveConfigFilePath(dir); err != nil || path != filepath.Join(dir, configFileName) {
+		t.Errorf("resolveConfigFilePath(%q) = %q, %v, expected the config file in the directory", dir, path, err)
+	}
+	file := filepath.Join(dir, "accounts.yaml")
+	if path, err := resolveConfigFilePath(file); err != nil || path != file {
+		t.Errorf("resolveConfigFilePath(%q) = %q, %v, expected the file itself", file, path, err)
+	}
+}
+
+// testUnitConfigFile writes a configuration file into a temporary directory and returns its path.
+func testUnitConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), configFileName)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -1,247 +1,225 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/bgppeergroup"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
 func dataSourceBgpPeerGroup() *schema.Resource {
-	return &schema.Resource{
+	bgpPeerGroupSchema := dataSourceBgpPeerGroupSchema()
+	bgpPeerGroupSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"location"},
+	}
+	bgpPeerGroupSchema["location"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
 
-		Read: dataSourceBgpPeerGroupRead,
+	return &schema.Resource{
+		ReadContext: dataSourceBgpPeerGroupRead,
+		Schema:      bgpPeerGroupSchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"location"},
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"location": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"ipv4_prefixes": { // Deprecated
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"ipv4_allocation_id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"cidr": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"status": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"is_bring_your_own_ip": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"in_use": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
+// dataSourceBgpPeerGroupSchema returns the computed attributes of a BGP peer group.
+func dataSourceBgpPeerGroupSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"ipv4_prefixes": { // Deprecated
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"ipv4_allocation_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"cidr": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"status": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"is_bring_your_own_ip": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+					"in_use": {
+						Type:     schema.TypeBool,
+						Computed: true,
 					},
 				},
 			},
-			"ip_prefixes": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"ip_allocation_id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"cidr": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"ip_version": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"status": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+		},
+		"ip_prefixes": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"ip_allocation_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"cidr": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"ip_version": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"status": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"target_asn_details": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"asn": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"is_bring_your_own": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"verification_status": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"verification_reason": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+		},
+		"target_asn_details": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"asn": {
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+					"is_bring_your_own": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+					"verification_status": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"verification_reason": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"active_asn_details": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"asn": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"is_bring_your_own": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"verification_status": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"verification_reason": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+		},
+		"active_asn_details": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"asn": {
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+					"is_bring_your_own": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+					"verification_status": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"verification_reason": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"password": {
-				Type:      schema.TypeString,
-				Computed:  true,
-				Sensitive: true,
-			},
-			"advertised_routes": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"rpki_roa_origin_asn": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"ebgp_multi_hop": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"peering_loopbacks_v4": {
-				Type:     schema.TypeSet,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"peering_loopbacks_v6": {
-				Type:     schema.TypeSet,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"keep_alive_timer_seconds": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"hold_timer_seconds": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"created_on": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"last_updated_on": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		},
+		"password": {
+			Type:      schema.TypeString,
+			Computed:  true,
+			Sensitive: true,
+		},
+		"advertised_routes": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"rpki_roa_origin_asn": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"ebgp_multi_hop": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"peering_loopbacks_v4": {
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"peering_loopbacks_v6": {
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"keep_alive_timer_seconds": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"hold_timer_seconds": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"created_on": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_updated_on": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceBgpPeerGroupRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceBgpPeerGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	bgpID := d.Get("id").(string)
 	if len(bgpID) > 0 {
 		requestCommand := bgppeergroup.NewGetBgpPeerGroupsCommand(client)
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		numOfGroups := 0
 		for _, instance := range resp {
 			if instance.Id == bgpID {
 				numOfGroups++
 				d.SetId(instance.Id)
-				d.Set("status", instance.Status)
-				d.Set("location", instance.Location)
-
-				ipv4Prefixes := flattenIpv4Prefixes(instance.Ipv4Prefixes)
-				if err := d.Set("ipv4_prefixes", ipv4Prefixes); err != nil {
-					return err
-				}
-				ipPrefixes := flattenIpPrefixes(instance.IpPrefixes)
-				if err := d.Set("ip_prefixes", ipPrefixes); err != nil {
-					return err
-				}
-				target := instance.TargetAsnDetails
-				targetAsnDetails := flattenAsnDetails(&target)
-				if err := d.Set("target_asn_details", targetAsnDetails); err != nil {
-					return err
-				}
-				activeAsnDetails := flattenAsnDetails(instance.ActiveAsnDetails)
-				if err := d.Set("active_asn_details", activeAsnDetails); err != nil {
-					return err
-				}
-				d.Set("password", instance.Password)
-				d.Set("advertised_routes", instance.AdvertisedRoutes)
-				d.Set("rpki_roa_origin_asn", int(instance.RpkiRoaOriginAsn))
-				d.Set("ebgp_multi_hop", int(instance.EBgpMultiHop))
-				var peeringLoopbacks []interface{}
-				for _, v := range instance.PeeringLoopbacksV4 {
-					peeringLoopbacks = append(peeringLoopbacks, v)
-				}
-				d.Set("peering_loopbacks_v4", peeringLoopbacks)
-				var peeringLoopbacks6 []interface{}
-				for _, v6 := range instance.PeeringLoopbacksV6 {
-					peeringLoopbacks6 = append(peeringLoopbacks6, v6)
-				}
-				d.Set("peering_loopbacks_v6", peeringLoopbacks6)
-				d.Set("keep_alive_timer_seconds", int(instance.KeepAliveTimerSeconds))
-				d.Set("hold_timer_seconds", int(instance.HoldTimerSeconds))
-
-				if instance.CreatedOn != nil {
-					createdOn := *instance.CreatedOn
-					d.Set("created_on", createdOn)
-				}
-				if instance.LastUpdatedOn != nil {
-					lastUpdatedOn := *instance.LastUpdatedOn
-					d.Set("last_updated_on", lastUpdatedOn)
+				for k, v := range flattenDataBgpPeerGroup(instance) {
+					if k == "id" {
+						continue
+					}
+					if err := d.Set(k, v); err != nil {
+						return diag.FromErr(err)
+					}
 				}
 			}
 		}
 		if numOfGroups > 1 {
-			return fmt.Errorf("too many BGP Peer Groups with id %s (found %d, expected 1)", d.Get("id").(string), numOfGroups)
+			return diag.Errorf("too many BGP Peer Groups with id %s (found %d, expected 1)", d.Get("id").(string), numOfGroups)
 		}
 		return nil
 	} else {
@@ -249,63 +227,62 @@ func dataSourceBgpPeerGroupRead(d *schema.ResourceData, m interface{}) error {
 		location := d.Get("location").(string)
 		query.LocationString = location
 		requestCommand := bgppeergroup.NewGetBgpPeerGroupsWithQueryCommand(client, &query)
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		numOfGroups := 0
 		for _, instance := range resp {
 			numOfGroups++
 			d.SetId(instance.Id)
-			d.Set("status", instance.Status)
-			d.Set("location", instance.Location)
-
-			ipv4Prefixes := flattenIpv4Prefixes(instance.Ipv4Prefixes)
-			if err := d.Set("ipv4_prefixes", ipv4Prefixes); err != nil {
-				return err
-			}
-			ipPrefixes := flattenIpPrefixes(instance.IpPrefixes)
-			if err := d.Set("ip_prefixes", ipPrefixes); err != nil {
-				return err
-			}
-			target := instance.TargetAsnDetails
-			targetAsnDetails := flattenAsnDetails(&target)
-			if err := d.Set("target_asn_details", targetAsnDetails); err != nil {
-				return err
-			}
-			activeAsnDetails := flattenAsnDetails(instance.ActiveAsnDetails)
-			if err := d.Set("active_asn_details", activeAsnDetails); err != nil {
-				return err
-			}
-			d.Set("password", instance.Password)
-			d.Set("advertised_routes", instance.AdvertisedRoutes)
-			d.Set("rpki_roa_origin_asn", int(instance.RpkiRoaOriginAsn))
-			d.Set("ebgp_multi_hop", int(instance.EBgpMultiHop))
-			var peeringLoopbacks []interface{}
-			for _, v := range instance.PeeringLoopbacksV4 {
-				peeringLoopbacks = append(peeringLoopbacks, v)
-			}
-			d.Set("peering_loopbacks_v4", peeringLoopbacks)
-			var peeringLoopbacks6 []interface{}
-			for _, v6 := range instance.PeeringLoopbacksV6 {
-				peeringLoopbacks6 = append(peeringLoopbacks6, v6)
-			}
-			d.Set("peering_loopbacks_v6", peeringLoopbacks6)
-			d.Set("keep_alive_timer_seconds", int(instance.KeepAliveTimerSeconds))
-			d.Set("hold_timer_seconds", int(instance.HoldTimerSeconds))
-
-			if instance.CreatedOn != nil {
-				createdOn := *instance.CreatedOn
-				d.Set("created_on", createdOn)
-			}
-			if instance.LastUpdatedOn != nil {
-				lastUpdatedOn := *instance.LastUpdatedOn
-				d.Set("last_updated_on", lastUpdatedOn)
+			for k, v := range flattenDataBgpPeerGroup(instance) {
+				if k == "id" {
+					continue
+				}
+				if err := d.Set(k, v); err != nil {
+					return diag.FromErr(err)
+				}
 			}
 		}
 		if numOfGroups > 1 {
-			return fmt.Errorf("too many BGP Peer Groups with location %s (found %d, expected 1)", d.Get("location").(string), numOfGroups)
+			return diag.Errorf("too many BGP Peer Groups with location %s (found %d, expected 1)", d.Get("location").(string), numOfGroups)
 		}
 		return nil
 	}
 }
+
+// flattenDataBgpPeerGroup maps a BGP peer group to the attributes of dataSourceBgpPeerGroupSchema
+func flattenDataBgpPeerGroup(instance networkapiclient.BgpPeerGroup) map[string]interface{} {
+	bgpPeerGroup := make(map[string]interface{})
+	bgpPeerGroup["id"] = instance.Id
+	bgpPeerGroup["status"] = instance.Status
+	bgpPeerGroup["location"] = instance.Location
+	bgpPeerGroup["ipv4_prefixes"] = flattenIpv4Prefixes(instance.Ipv4Prefixes)
+	bgpPeerGroup["ip_prefixes"] = flattenIpPrefixes(instance.IpPrefixes)
+	target := instance.TargetAsnDetails
+	bgpPeerGroup["target_asn_details"] = flattenAsnDetails(&target)
+	bgpPeerGroup["active_asn_details"] = flattenAsnDetails(instance.ActiveAsnDetails)
+	bgpPeerGroup["password"] = instance.Password
+	bgpPeerGroup["advertised_routes"] = instance.AdvertisedRoutes
+	bgpPeerGroup["rpki_roa_origin_asn"] = int(instance.RpkiRoaOriginAsn)
+	bgpPeerGroup["ebgp_multi_hop"] = int(instance.EBgpMultiHop)
+	var peeringLoopbacks []interface{}
+	for _, v := range instance.PeeringLoopbacksV4 {
+		peeringLoopbacks = append(peeringLoopbacks, v)
+	}
+	bgpPeerGroup["peering_loopbacks_v4"] = peeringLoopbacks
+	var peeringLoopbacks6 []interface{}
+	for _, v6 := range instance.PeeringLoopbacksV6 {
+		peeringLoopbacks6 = append(peeringLoopbacks6, v6)
+	}
+	bgpPeerGroup["peering_loopbacks_v6"] = peeringLoopbacks6
+	bgpPeerGroup["keep_alive_timer_seconds"] = int(instance.KeepAliveTimerSeconds)
+	bgpPeerGroup["hold_timer_seconds"] = int(instance.HoldTimerSeconds)
+	if instance.CreatedOn != nil {
+		bgpPeerGroup["created_on"] = *instance.CreatedOn
+	}
+	if instance.LastUpdatedOn != nil {
+		bgpPeerGroup["last_updated_on"] = *instance.LastUpdatedOn
+	}
+	return bgpPeerGroup
+}
@@ -0,0 +1,62 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/bgppeergroup"
+)
+
+func dataSourceBgpPeerGroups() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceBgpPeerGroupsRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"bgp_peer_groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceBgpPeerGroupSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceBgpPeerGroupsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := bgppeergroup.NewGetBgpPeerGroupsCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	bgpPeerGroups := make([]interface{}, 0)
+	for _, instance := range resp {
+		if v, ok := d.GetOk("location"); ok && instance.Location != v.(string) {
+			continue
+		}
+		if v, ok := d.GetOk("status"); ok && instance.Status != v.(string) {
+			continue
+		}
+		bgpPeerGroups = append(bgpPeerGroups, flattenDataBgpPeerGroup(instance))
+	}
+	if err := d.Set("bgp_peer_groups", bgpPeerGroups); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
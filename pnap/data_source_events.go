@@ -1,19 +1,19 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 	"strconv"
 	"time"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/auditapi/event"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceEvents() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceEventsRead,
+		ReadContext: dataSourceEventsRead,
 
 		Schema: map[string]*schema.Schema{
 			"from": {
@@ -86,15 +86,15 @@ func dataSourceEvents() *schema.Resource {
 	}
 }
 
-func dataSourceEventsRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceEventsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	query := dto.Query{}
 
 	from := d.Get("from").(string)
 	if from != "" {
 		t1, err1 := time.Parse(time.RFC3339, from)
 		if err1 != nil {
-			return err1
+			return diag.FromErr(err1)
 		} else {
 			query.From = t1
 		}
@@ -103,7 +103,7 @@ func dataSourceEventsRead(d *schema.ResourceData, m interface{}) error {
 	if to != "" {
 		t2, err2 := time.Parse(time.RFC3339, to)
 		if err2 != nil {
-			return err2
+			return diag.FromErr(err2)
 		} else {
 			query.To = t2
 		}
@@ -115,16 +115,16 @@ func dataSourceEventsRead(d *schema.ResourceData, m interface{}) error {
 	query.Uri = d.Get("uri").(string)
 
 	requestCommand := event.NewGetEventsCommandWithQuery(client, &query)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	qEvents := d.Get("events").([]interface{})
 	var events []interface{}
 
 	if len(qEvents) > 0 {
 		if len(qEvents) != 1 {
-			return fmt.Errorf("unsupported action")
+			return diag.Errorf("unsupported action")
 		}
 		qEvent := qEvents[0]
 		qEventItem := qEvent.(map[string]interface{})
@@ -0,0 +1,56 @@
+package pnap
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceFilter reports whether an item matches the value set for one of the arguments of a list data source.
+type dataSourceFilter[T any] func(item T, value interface{}) bool
+
+// filterDataSourceItems returns the flattened items passing the filters of every argument that is set. Arguments
+// left unset, or set to an empty string, don't filter anything.
+func filterDataSourceItems[T any](d *schema.ResourceData, items []T, filters map[string]dataSourceFilter[T], flatten func(T) map[string]interface{}) []interface{} {
+	values := make(map[string]interface{})
+	for argument := range filters {
+		if v, ok := d.GetOkExists(argument); ok && v != "" {
+			values[argument] = v
+		}
+	}
+
+	result := make([]interface{}, 0)
+	for _, item := range items {
+		matches := true
+		for argument, value := range values {
+			if !filters[argument](item, value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			result = append(result, flatten(item))
+		}
+	}
+	return result
+}
+
+// equalsFilter matches a string attribute of the item against the value of the argument.
+func equalsFilter[T any](attribute func(T) string) dataSourceFilter[T] {
+	return func(item T, value interface{}) bool {
+		return attribute(item) == value.(string)
+	}
+}
+
+// tagFilter is the filter of tag_name. An item matches when one of its tags has that name and, if tag_value
+// is set, that value.
+func tagFilter[T, Tag any](d *schema.ResourceData, tags func(T) []Tag, tag func(Tag) (string, *string)) dataSourceFilter[T] {
+	tagValue, filterByValue := d.GetOk("tag_value")
+	return func(item T, value interface{}) bool {
+		for _, t := range tags(item) {
+			name, v := tag(t)
+			if name == value.(string) && (!filterByValue || v != nil && *v == tagValue.(string)) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,72 @@
+package pnap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type testFilterTag struct {
+	name  string
+	value *string
+}
+
+type testFilterItem struct {
+	name     string
+	location string
+	tags     []testFilterTag
+}
+
+func TestFilterDataSourceItems(t *testing.T) {
+	filterSchema := map[string]*schema.Schema{
+		"location":  {Type: schema.TypeString, Optional: true},
+		"tag_name":  {Type: schema.TypeString, Optional: true},
+		"tag_value": {Type: schema.TypeString, Optional: true},
+	}
+	production, staging := "production", "staging"
+	items := []testFilterItem{
+		{name: "a", location: "PHX", tags: []testFilterTag{{name: "env", value: &production}}},
+		{name: "b", location: "PHX", tags: []testFilterTag{{name: "env", value: &staging}}},
+		{name: "c", location: "ASH", tags: []testFilterTag{{name: "env", value: &production}}},
+		{name: "d", location: "PHX", tags: []testFilterTag{{name: "team"}}},
+	}
+
+	cases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected []interface{}
+	}{
+		{"unfiltered", map[string]interface{}{}, []interface{}{"a", "b", "c", "d"}},
+		{"empty argument", map[string]interface{}{"location": ""}, []interface{}{"a", "b", "c", "d"}},
+		{"attribute", map[string]interface{}{"location": "PHX"}, []interface{}{"a", "b", "d"}},
+		{"tag name", map[string]interface{}{"tag_name": "env"}, []interface{}{"a", "b", "c"}},
+		{"tag value", map[string]interface{}{"tag_name": "env", "tag_value": "production"}, []interface{}{"a", "c"}},
+		{"every filter", map[string]interface{}{"location": "PHX", "tag_name": "env", "tag_value": "production"}, []interface{}{"a"}},
+		{"no match", map[string]interface{}{"tag_name": "team", "tag_value": "storage"}, []interface{}{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, filterSchema, c.config)
+			filters := map[string]dataSourceFilter[testFilterItem]{
+				"location": equalsFilter(func(i testFilterItem) string { return i.location }),
+				"tag_name": tagFilter(d,
+					func(i testFilterItem) []testFilterTag { return i.tags },
+					func(t testFilterTag) (string, *string) { return t.name, t.value }),
+			}
+			flatten := func(i testFilterItem) map[string]interface{} {
+				return map[string]interface{}{"name": i.name}
+			}
+			var names []interface{}
+			for _, item := range filterDataSourceItems(d, items, filters, flatten) {
+				names = append(names, item.(map[string]interface{})["name"])
+			}
+			if names == nil {
+				names = []interface{}{}
+			}
+			if !reflect.DeepEqual(names, c.expected) {
+				t.Errorf("filtered %v, expected %v", names, c.expected)
+			}
+		})
+	}
+}
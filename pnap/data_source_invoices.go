@@ -1,7 +1,7 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 	"io"
 	"math"
 	"os"
@@ -10,13 +10,13 @@ import (
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/invoicingapi/invoice"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceInvoices() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceInvoicesRead,
+		ReadContext: dataSourceInvoicesRead,
 
 		Schema: map[string]*schema.Schema{
 			"number": {
@@ -123,8 +123,8 @@ func dataSourceInvoices() *schema.Resource {
 	}
 }
 
-func dataSourceInvoicesRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceInvoicesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	query := dto.Query{}
 	query.Number = d.Get("number").(string)
 	query.Status = d.Get("status").(string)
@@ -133,7 +133,7 @@ func dataSourceInvoicesRead(d *schema.ResourceData, m interface{}) error {
 	if sentOnFrom != "" {
 		t1, err1 := time.Parse(time.RFC3339, sentOnFrom)
 		if err1 != nil {
-			return err1
+			return diag.FromErr(err1)
 		} else {
 			query.SentOnFrom = t1
 		}
@@ -142,7 +142,7 @@ func dataSourceInvoicesRead(d *schema.ResourceData, m interface{}) error {
 	if sentOnTo != "" {
 		t2, err2 := time.Parse(time.RFC3339, sentOnTo)
 		if err2 != nil {
-			return err2
+			return diag.FromErr(err2)
 		} else {
 			query.SentOnTo = t2
 		}
@@ -153,9 +153,9 @@ func dataSourceInvoicesRead(d *schema.ResourceData, m interface{}) error {
 	query.SortDirection = d.Get("sort_direction").(string)
 
 	requestCommand := invoice.NewGetInvoicesCommand(client, query)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	paginatedInvoices := make([]interface{}, 1)
@@ -190,22 +190,22 @@ func dataSourceInvoicesRead(d *schema.ResourceData, m interface{}) error {
 
 				if len(path) > 0 {
 					pdfRequestCommand := invoice.NewGenerateInvoicePdfCommand(client, id)
-					pdf, err := pdfRequestCommand.Execute()
+					pdf, err := execute(ctx, m.(*providerMeta), retryIdempotent, pdfRequestCommand.Execute)
 					if err != nil {
-						return err
+						return diag.FromErr(err)
 					}
 					data, err := io.ReadAll(pdf)
 					if err != nil {
-						return err
+						return diag.FromErr(err)
 					}
 					invoicePdf, err := os.Create(path + j.Number + ".pdf")
 					if err != nil {
-						return err
+						return diag.FromErr(err)
 					}
 					defer invoicePdf.Close()
 
 					if _, err := invoicePdf.Write(data); err != nil {
-						return (err)
+						return diag.FromErr(err)
 					}
 				}
 				result := make([]interface{}, 1)
@@ -219,7 +219,7 @@ func dataSourceInvoicesRead(d *schema.ResourceData, m interface{}) error {
 			}
 		}
 		if numOfInvoices > 1 {
-			return fmt.Errorf("too many invoices with id %s (found %d, expected 1)", id, numOfInvoices)
+			return diag.Errorf("too many invoices with id %s (found %d, expected 1)", id, numOfInvoices)
 		}
 
 	} else {
@@ -244,22 +244,22 @@ func dataSourceInvoicesRead(d *schema.ResourceData, m interface{}) error {
 			invoiceMap["due_date"] = j.DueDate.String()
 			if len(path) > 0 {
 				pdfRequestCommand := invoice.NewGenerateInvoicePdfCommand(client, id)
-				pdf, err := pdfRequestCommand.Execute()
+				pdf, err := execute(ctx, m.(*providerMeta), retryIdempotent, pdfRequestCommand.Execute)
 				if err != nil {
-					return err
+					return diag.FromErr(err)
 				}
 				data, err := io.ReadAll(pdf)
 				if err != nil {
-					return err
+					return diag.FromErr(err)
 				}
 				invoicePdf, err := os.Create(path + j.Number + ".pdf")
 				if err != nil {
-					return err
+					return diag.FromErr(err)
 				}
 				defer invoicePdf.Close()
 
 				if _, err := invoicePdf.Write(data); err != nil {
-					return (err)
+					return diag.FromErr(err)
 				}
 			}
 
@@ -1,113 +1,127 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/phoenixnap/go-sdk-bmc/ipapi/v3"
 )
 
 func dataSourceIpBlock() *schema.Resource {
+	ipBlockSchema := dataSourceIpBlockSchema()
+	ipBlockSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"cidr"},
+	}
+	ipBlockSchema["cidr"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
+
 	return &schema.Resource{
-		Read: dataSourceIpBlockRead,
+		ReadContext: dataSourceIpBlockRead,
+		Schema:      ipBlockSchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"location": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"cidr_block_size": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"cidr"},
-			},
-			"cidr": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"ip_version": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"parent_ip_block_allocation_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"assigned_resource_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"assigned_resource_type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"description": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"tags": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"name": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"value": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"is_billing_tag": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"created_by": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+// dataSourceIpBlockSchema returns the computed attributes of an IP block.
+func dataSourceIpBlockSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"location": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"cidr_block_size": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"cidr": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"ip_version": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"parent_ip_block_allocation_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"assigned_resource_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"assigned_resource_type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"tags": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"is_billing_tag": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+					"created_by": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"is_system_managed": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"is_bring_your_own": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"created_on": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		},
+		"is_system_managed": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"is_bring_your_own": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"created_on": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceIpBlockRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	requestCommand := ipblock.NewGetIpBlocksCommand(client)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	numOfBlocks := 0
@@ -121,80 +135,72 @@ func dataSourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
 			} else {
 				d.SetId("")
 			}
-			if instance.Location != nil {
-				d.Set("location", *instance.Location)
-			} else {
-				d.Set("location", "")
-			}
-			if instance.CidrBlockSize != nil {
-				d.Set("cidr_block_size", *instance.CidrBlockSize)
-			} else {
-				d.Set("cidr_block_size", "")
-			}
-			if instance.Cidr != nil {
-				d.Set("cidr", *instance.Cidr)
-			} else {
-				d.Set("cidr", "")
-			}
-			if instance.IpVersion != nil {
-				d.Set("ip_version", *instance.IpVersion)
-			} else {
-				d.Set("ip_version", "")
-			}
-			if instance.Status != nil {
-				d.Set("status", *instance.Status)
-			} else {
-				d.Set("status", "")
-			}
-			if instance.ParentIpBlockAllocationId != nil {
-				d.Set("parent_ip_block_allocation_id", *instance.ParentIpBlockAllocationId)
-			} else {
-				d.Set("parent_ip_block_allocation_id", "")
-			}
-			if instance.AssignedResourceId != nil {
-				d.Set("assigned_resource_id", *instance.AssignedResourceId)
-			} else {
-				d.Set("assigned_resource_id", "")
-			}
-			if instance.AssignedResourceType != nil {
-				d.Set("assigned_resource_type", *instance.AssignedResourceType)
-			} else {
-				d.Set("assigned_resource_type", "")
-			}
-			if instance.Description != nil {
-				d.Set("description", *instance.Description)
-			} else {
-				d.Set("description", "")
-			}
-			tags := flattenDataTags(instance.Tags)
-			if err := d.Set("tags", tags); err != nil {
-				return err
-			}
-			if instance.IsSystemManaged != nil {
-				d.Set("is_system_managed", *instance.IsSystemManaged)
-			} else {
-				d.Set("is_system_managed", nil)
-			}
-			if instance.IsBringYourOwn != nil {
-				d.Set("is_bring_your_own", *instance.IsBringYourOwn)
-			} else {
-				d.Set("is_bring_your_own", nil)
-			}
-			if instance.CreatedOn != nil {
-				createdOn := *instance.CreatedOn
-				d.Set("created_on", createdOn.String())
+			for k, v := range flattenDataIpBlock(instance) {
+				if k == "id" {
+					continue
+				}
+				if err := d.Set(k, v); err != nil {
+					return diag.FromErr(err)
+				}
 			}
 		}
 	}
 	if numOfBlocks > 1 && len(cidr) > 0 {
-		return fmt.Errorf("too many IP Blocks with CIDR %s (found %d, expected 1)", cidr, numOfBlocks)
+		return diag.Errorf("too many IP Blocks with CIDR %s (found %d, expected 1)", cidr, numOfBlocks)
 	} else if numOfBlocks > 1 && len(id) > 0 {
-		return fmt.Errorf("too many IP Blocks with ID %s (found %d, expected 1)", id, numOfBlocks)
+		return diag.Errorf("too many IP Blocks with ID %s (found %d, expected 1)", id, numOfBlocks)
 	}
 
 	return nil
 }
 
+// flattenDataIpBlock maps an IP block to the attributes of dataSourceIpBlockSchema
+func flattenDataIpBlock(instance ipapi.IpBlock) map[string]interface{} {
+	ipBlock := make(map[string]interface{})
+	if instance.Id != nil {
+		ipBlock["id"] = *instance.Id
+	}
+	if instance.Location != nil {
+		ipBlock["location"] = *instance.Location
+	}
+	if instance.CidrBlockSize != nil {
+		ipBlock["cidr_block_size"] = *instance.CidrBlockSize
+	}
+	if instance.Cidr != nil {
+		ipBlock["cidr"] = *instance.Cidr
+	}
+	if instance.IpVersion != nil {
+		ipBlock["ip_version"] = *instance.IpVersion
+	}
+	if instance.Status != nil {
+		ipBlock["status"] = *instance.Status
+	}
+	if instance.ParentIpBlockAllocationId != nil {
+		ipBlock["parent_ip_block_allocation_id"] = *instance.ParentIpBlockAllocationId
+	}
+	if instance.AssignedResourceId != nil {
+		ipBlock["assigned_resource_id"] = *instance.AssignedResourceId
+	}
+	if instance.AssignedResourceType != nil {
+		ipBlock["assigned_resource_type"] = *instance.AssignedResourceType
+	}
+	if instance.Description != nil {
+		ipBlock["description"] = *instance.Description
+	}
+	ipBlock["tags"] = flattenDataTags(instance.Tags)
+	if instance.IsSystemManaged != nil {
+		ipBlock["is_system_managed"] = *instance.IsSystemManaged
+	}
+	if instance.IsBringYourOwn != nil {
+		ipBlock["is_bring_your_own"] = *instance.IsBringYourOwn
+	}
+	if instance.CreatedOn != nil {
+		createdOn := *instance.CreatedOn
+		ipBlock["created_on"] = createdOn.String()
+	}
+	return ipBlock
+}
+
 // Returns list of assigned tags
 func flattenDataTags(tags []ipapi.TagAssignment) []interface{} {
 	if tags != nil {
@@ -0,0 +1,69 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/phoenixnap/go-sdk-bmc/ipapi/v3"
+)
+
+func dataSourceIpBlocks() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceIpBlocksRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag_value": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"tag_name"},
+			},
+			"ip_blocks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceIpBlockSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceIpBlocksRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := ipblock.NewGetIpBlocksCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filters := map[string]dataSourceFilter[ipapi.IpBlock]{
+		"location": equalsFilter(func(b ipapi.IpBlock) string { return b.GetLocation() }),
+		"status":   equalsFilter(func(b ipapi.IpBlock) string { return b.GetStatus() }),
+		"tag_name": tagFilter(d,
+			func(b ipapi.IpBlock) []ipapi.TagAssignment { return b.Tags },
+			func(t ipapi.TagAssignment) (string, *string) { return t.Name, t.Value }),
+	}
+	ipBlocks := filterDataSourceItems(d, resp, filters, flattenDataIpBlock)
+	if err := d.Set("ip_blocks", ipBlocks); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package pnap
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapIpBlocksDataSource_filters(t *testing.T) {
+	api := newMockAPI(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitIpBlocksDataSource(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pnap_ip_blocks.all", "ip_blocks.#", "2"),
+					resource.TestCheckResourceAttr("data.pnap_ip_blocks.tagged", "ip_blocks.#", "1"),
+					resource.TestCheckResourceAttrPair("data.pnap_ip_blocks.tagged", "ip_blocks.0.id", "pnap_ip_block.prod", "id"),
+					resource.TestCheckResourceAttr("data.pnap_ip_blocks.tagged", "ip_blocks.0.tags.0.value", "prod"),
+					resource.TestCheckResourceAttr("data.pnap_ip_blocks.unassigned", "ip_blocks.#", "2"),
+					resource.TestCheckResourceAttr("data.pnap_ip_blocks.none", "ip_blocks.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testUnitIpBlocksDataSource() string {
+	return `
+resource "pnap_ip_block" "prod" {
+	location = "PHX"
+	cidr_block_size = "/29"
+	tags {
+		tag_assignment {
+			name = "env"
+			value = "prod"
+		}
+	}
+}
+
+resource "pnap_ip_block" "dev" {
+	location = "PHX"
+	cidr_block_size = "/29"
+	tags {
+		tag_assignment {
+			name = "env"
+			value = "dev"
+		}
+	}
+}
+
+data "pnap_ip_blocks" "all" {
+	location = "PHX"
+	depends_on = [pnap_ip_block.prod, pnap_ip_block.dev]
+}
+
+data "pnap_ip_blocks" "tagged" {
+	tag_name = "env"
+	tag_value = "prod"
+	depends_on = [pnap_ip_block.prod, pnap_ip_block.dev]
+}
+
+data "pnap_ip_blocks" "unassigned" {
+	status = "unassigned"
+	depends_on = [pnap_ip_block.prod, pnap_ip_block.dev]
+}
+
+data "pnap_ip_blocks" "none" {
+	location = "ASH"
+	depends_on = [pnap_ip_block.prod, pnap_ip_block.dev]
+}`
+}
@@ -1,19 +1,20 @@
 package pnap
 
 import (
+	"context"
 	"strconv"
 	"time"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/locationapi/location"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	locationapiclient "github.com/phoenixnap/go-sdk-bmc/locationapi/v4"
 )
 
 func dataSourceLocations() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceLocationsRead,
+		ReadContext: dataSourceLocationsRead,
 
 		Schema: map[string]*schema.Schema{
 			"location": {
@@ -60,15 +61,15 @@ func dataSourceLocations() *schema.Resource {
 	}
 }
 
-func dataSourceLocationsRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceLocationsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	query := dto.Query{}
 
 	loc := d.Get("location").(string)
 	if len(loc) > 0 {
 		locEnum, errorLoc := locationapiclient.NewProductLocationEnumFromValue(loc)
 		if errorLoc != nil {
-			return errorLoc
+			return diag.FromErr(errorLoc)
 		}
 		query.Location = *locEnum
 	}
@@ -76,15 +77,15 @@ func dataSourceLocationsRead(d *schema.ResourceData, m interface{}) error {
 	if len(productCategory) > 0 {
 		prodCatEnum, errorProd := locationapiclient.NewProductCategoryEnumFromValue(productCategory)
 		if errorProd != nil {
-			return errorProd
+			return diag.FromErr(errorProd)
 		}
 		query.ProductCategory = *prodCatEnum
 	}
 
 	requestCommand := location.NewGetLocationsCommand(client, query)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	var locations []interface{}
 	for _, j := range resp {
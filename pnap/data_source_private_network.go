@@ -1,112 +1,127 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/privatenetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
 func dataSourcePrivateNetwork() *schema.Resource {
-	return &schema.Resource{
+	privateNetworkSchema := dataSourcePrivateNetworkSchema()
+	privateNetworkSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name"},
+	}
+	privateNetworkSchema["name"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
 
-		Read: dataSourcePrivateNetworkRead,
+	return &schema.Resource{
+		ReadContext: dataSourcePrivateNetworkRead,
+		Schema:      privateNetworkSchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"name"},
-			},
-			"name": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"location": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"description": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"location_default": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"cidr": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"type": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"vlan_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"servers": { // Deprecated
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"ips": {
-							Type:     schema.TypeSet,
-							Computed: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-						},
+// dataSourcePrivateNetworkSchema returns the computed attributes of a private network.
+func dataSourcePrivateNetworkSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:          schema.TypeString,
+			Computed:      true,
+		},
+		"name": {
+			Type:          schema.TypeString,
+			Computed:      true,
+		},
+		"location": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location_default": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"cidr": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"type": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"vlan_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"servers": { // Deprecated
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"ips": {
+						Type:     schema.TypeSet,
+						Computed: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
 					},
 				},
 			},
-			"memberships": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"resource_id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"resource_type": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"ips": {
-							Type:     schema.TypeSet,
-							Computed: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-						},
+		},
+		"memberships": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"resource_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"resource_type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"ips": {
+						Type:     schema.TypeSet,
+						Computed: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
 					},
 				},
 			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"created_on": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"created_on": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourcePrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourcePrivateNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	requestCommand := privatenetwork.NewGetPrivateNetworksCommand(client)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	numOfNets := 0
@@ -114,32 +129,42 @@ func dataSourcePrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
 		if instance.Name == d.Get("name").(string) || instance.Id == d.Get("id").(string) {
 			numOfNets++
 			d.SetId(instance.Id)
-			d.Set("location", instance.Location)
-			d.Set("name", instance.Name)
-			d.Set("cidr", instance.Cidr)
-			d.Set("description", instance.Description)
-			d.Set("location_default", instance.LocationDefault)
-			d.Set("type", instance.Type)
-			d.Set("vlan_id", instance.VlanId)
-			servers := flattenServers(instance.Servers)
-
-			if err := d.Set("servers", servers); err != nil {
-				return err
-			}
-			memberships := flattenMemberships(instance.Memberships)
-
-			if err := d.Set("memberships", memberships); err != nil {
-				return err
-			}
-			d.Set("status", instance.Status)
-
-			if len(instance.CreatedOn.String()) > 0 {
-				d.Set("created_on", instance.CreatedOn.String())
+			for k, v := range flattenDataPrivateNetwork(instance) {
+				if k == "id" {
+					continue
+				}
+				if err := d.Set(k, v); err != nil {
+					return diag.FromErr(err)
+				}
 			}
 		}
 	}
 	if numOfNets > 1 {
-		return fmt.Errorf("too many private networks with name %s (found %d, expected 1)", d.Get("name").(string), numOfNets)
+		return diag.Errorf("too many private networks with name %s (found %d, expected 1)", d.Get("name").(string), numOfNets)
 	}
 	return nil
 }
+
+// flattenDataPrivateNetwork maps a private network to the attributes of dataSourcePrivateNetworkSchema
+func flattenDataPrivateNetwork(instance networkapiclient.PrivateNetwork) map[string]interface{} {
+	privateNetwork := make(map[string]interface{})
+	privateNetwork["id"] = instance.Id
+	privateNetwork["location"] = instance.Location
+	privateNetwork["name"] = instance.Name
+	if instance.Cidr != nil {
+		privateNetwork["cidr"] = *instance.Cidr
+	}
+	if instance.Description != nil {
+		privateNetwork["description"] = *instance.Description
+	}
+	privateNetwork["location_default"] = instance.LocationDefault
+	privateNetwork["type"] = instance.Type
+	privateNetwork["vlan_id"] = int(instance.VlanId)
+	privateNetwork["servers"] = flattenServers(instance.Servers)
+	privateNetwork["memberships"] = flattenMemberships(instance.Memberships)
+	privateNetwork["status"] = instance.Status
+	if len(instance.CreatedOn.String()) > 0 {
+		privateNetwork["created_on"] = instance.CreatedOn.String()
+	}
+	return privateNetwork
+}
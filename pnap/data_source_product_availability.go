@@ -1,6 +1,7 @@
 package pnap
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -9,13 +10,13 @@ import (
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/product"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceProductAvailability() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceProductAvailabilityRead,
+		ReadContext: dataSourceProductAvailabilityRead,
 
 		Schema: map[string]*schema.Schema{
 			"product_category": {
@@ -97,8 +98,8 @@ func dataSourceProductAvailability() *schema.Resource {
 	}
 }
 
-func dataSourceProductAvailabilityRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceProductAvailabilityRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	query := dto.ProductAvailabilityQuery{}
 	proCatTemp := d.Get("product_category").(*schema.Set).List()
@@ -136,9 +137,9 @@ func dataSourceProductAvailabilityRead(d *schema.ResourceData, m interface{}) er
 	log.Printf("request object is" + string(b))
 
 	requestCommand := product.NewGetProductAvailabilityCommand(client, query)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	var productAvailabilities []interface{}
 	for _, j := range resp {
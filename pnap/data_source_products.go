@@ -1,19 +1,20 @@
 package pnap
 
 import (
+	"context"
 	"math"
 	"strconv"
 	"time"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/product"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceProducts() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceProductsRead,
+		ReadContext: dataSourceProductsRead,
 
 		Schema: map[string]*schema.Schema{
 			"product_code": {
@@ -212,8 +213,8 @@ func dataSourceProducts() *schema.Resource {
 	}
 }
 
-func dataSourceProductsRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceProductsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	query := dto.ProductQuery{}
 	query.ProductCode = d.Get("product_code").(string)
 	query.ProductCategory = d.Get("product_category").(string)
@@ -221,9 +222,9 @@ func dataSourceProductsRead(d *schema.ResourceData, m interface{}) error {
 	query.Location = d.Get("location").(string)
 
 	requestCommand := product.NewGetProductsCommand(client, query)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	products := make([]interface{}, 0, len(resp))
 	for _, j := range resp {
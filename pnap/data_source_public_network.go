@@ -1,108 +1,121 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 )
 
 func dataSourcePublicNetwork() *schema.Resource {
-	return &schema.Resource{
+	publicNetworkSchema := dataSourcePublicNetworkSchema()
+	publicNetworkSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name"},
+	}
+	publicNetworkSchema["name"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
 
-		Read: dataSourcePublicNetworkRead,
+	return &schema.Resource{
+		ReadContext: dataSourcePublicNetworkRead,
+		Schema:      publicNetworkSchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"name"},
-			},
-			"name": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"location": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"description": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"ip_blocks": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"cidr": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"used_ips_count": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+// dataSourcePublicNetworkSchema returns the computed attributes of a public network.
+func dataSourcePublicNetworkSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"ip_blocks": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"cidr": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"used_ips_count": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"created_on": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"vlan_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"memberships": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"resource_id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"resource_type": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"ips": {
-							Type:     schema.TypeSet,
-							Computed: true,
-							Elem:     &schema.Schema{Type: schema.TypeString},
-						},
+		},
+		"created_on": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"vlan_id": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"memberships": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"resource_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"resource_type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"ips": {
+						Type:     schema.TypeSet,
+						Computed: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
 					},
 				},
 			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"ra_enabled": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"ra_enabled": {
+			Type:     schema.TypeBool,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourcePublicNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	requestCommand := publicnetwork.NewGetPublicNetworksCommand(client)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	numOfNets := 0
@@ -112,41 +125,45 @@ func dataSourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
 		if instance.Name == name || instance.Id == id {
 			numOfNets++
 			d.SetId(instance.Id)
-			d.Set("location", instance.Location)
-			d.Set("name", instance.Name)
-			if instance.Description != nil {
-				d.Set("description", *instance.Description)
-			} else {
-				d.Set("description", "")
-			}
-			ipBlocks := flattenDataIpBlocks(instance.IpBlocks)
-			if err := d.Set("ip_blocks", ipBlocks); err != nil {
-				return err
-			}
-			d.Set("created_on", instance.CreatedOn.String())
-			d.Set("vlan_id", instance.VlanId)
-
-			memberships := flattenMemberships(instance.Memberships)
-			if err := d.Set("memberships", memberships); err != nil {
-				return err
-			}
-			d.Set("status", instance.Status)
-			if instance.RaEnabled != nil {
-				d.Set("ra_enabled", *instance.RaEnabled)
-			} else {
-				d.Set("ra_enabled", nil)
+			for k, v := range flattenDataPublicNetwork(instance) {
+				if k == "id" {
+					continue
+				}
+				if err := d.Set(k, v); err != nil {
+					return diag.FromErr(err)
+				}
 			}
 		}
 	}
 	if numOfNets > 1 && len(name) > 0 {
-		return fmt.Errorf("too many public networks with name %s (found %d, expected 1)", name, numOfNets)
+		return diag.Errorf("too many public networks with name %s (found %d, expected 1)", name, numOfNets)
 	} else if numOfNets > 1 && len(id) > 0 {
-		return fmt.Errorf("too many public networks with ID %s (found %d, expected 1)", id, numOfNets)
+		return diag.Errorf("too many public networks with ID %s (found %d, expected 1)", id, numOfNets)
 	}
 
 	return nil
 }
 
+// flattenDataPublicNetwork maps a public network to the attributes of dataSourcePublicNetworkSchema
+func flattenDataPublicNetwork(instance networkapiclient.PublicNetwork) map[string]interface{} {
+	publicNetwork := make(map[string]interface{})
+	publicNetwork["id"] = instance.Id
+	publicNetwork["location"] = instance.Location
+	publicNetwork["name"] = instance.Name
+	if instance.Description != nil {
+		publicNetwork["description"] = *instance.Description
+	}
+	publicNetwork["ip_blocks"] = flattenDataIpBlocks(instance.IpBlocks)
+	publicNetwork["created_on"] = instance.CreatedOn.String()
+	publicNetwork["vlan_id"] = int(instance.VlanId)
+	publicNetwork["memberships"] = flattenMemberships(instance.Memberships)
+	publicNetwork["status"] = instance.Status
+	if instance.RaEnabled != nil {
+		publicNetwork["ra_enabled"] = *instance.RaEnabled
+	}
+	return publicNetwork
+}
+
 func flattenDataIpBlocks(ipBlocks []networkapiclient.PublicNetworkIpBlock) []interface{} {
 	if len(ipBlocks) > 0 {
 		ib := make([]interface{}, len(ipBlocks))
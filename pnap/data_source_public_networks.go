@@ -0,0 +1,62 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
+)
+
+func dataSourcePublicNetworks() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePublicNetworksRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"public_networks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourcePublicNetworkSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePublicNetworksRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := publicnetwork.NewGetPublicNetworksCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	publicNetworks := make([]interface{}, 0)
+	for _, instance := range resp {
+		if v, ok := d.GetOk("location"); ok && instance.Location != v.(string) {
+			continue
+		}
+		if v, ok := d.GetOk("status"); ok && instance.Status != v.(string) {
+			continue
+		}
+		publicNetworks = append(publicNetworks, flattenDataPublicNetwork(instance))
+	}
+	if err := d.Set("public_networks", publicNetworks); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
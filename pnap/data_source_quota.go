@@ -1,67 +1,83 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/quota"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
 )
 
 func dataSourceQuota() *schema.Resource {
+	quotaSchema := dataSourceQuotaSchema()
+	quotaSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name"},
+	}
+	quotaSchema["name"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
+
 	return &schema.Resource{
-		Read: dataSourceQuotaRead,
+		ReadContext: dataSourceQuotaRead,
+		Schema:      quotaSchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"name"},
-			},
-			"name": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"description": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"limit": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"unit": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"used": {
-				Type:     schema.TypeInt,
-				Computed: true,
-			},
-			"quota_edit_limit_request_details": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"limit": {
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
-						"reason": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"requested_on": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+// dataSourceQuotaSchema returns the computed attributes of a quota.
+func dataSourceQuotaSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"limit": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"unit": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"used": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"quota_edit_limit_request_details": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"limit": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"reason": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"requested_on": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
@@ -69,39 +85,52 @@ func dataSourceQuota() *schema.Resource {
 	}
 }
 
-func dataSourceQuotaRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceQuotaRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	requestCommand := quota.NewGetQuotasCommand(client)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	numOfQuotas := 0
 	for _, instance := range resp {
 		if instance.Name == d.Get("name").(string) || instance.Id == d.Get("id").(string) {
 			numOfQuotas++
 			d.SetId(instance.Id)
-			d.Set("name", instance.Name)
-			d.Set("description", instance.Description)
-			d.Set("status", instance.Status)
-			d.Set("limit", int(instance.Limit))
-			d.Set("unit", instance.Unit)
-			d.Set("used", int(instance.Used))
-			quotaRequests := instance.QuotaEditLimitRequestDetails
-			qelrd := make([]interface{}, len(quotaRequests))
-			for i, j := range quotaRequests {
-				qelrdItem := make(map[string]interface{})
-				qelrdItem["limit"] = int(j.Limit)
-				qelrdItem["reason"] = j.Reason
-				qelrdItem["requested_on"] = j.RequestedOn.String()
-				qelrd[i] = qelrdItem
+			for k, v := range flattenDataQuota(instance) {
+				if k == "id" {
+					continue
+				}
+				d.Set(k, v)
 			}
-			d.Set("quota_edit_limit_request_details", qelrd)
 		}
 	}
 	if numOfQuotas > 1 {
-		return fmt.Errorf("too many Quotas with name %s (found %d, expected 1)", d.Get("name").(string), numOfQuotas)
+		return diag.Errorf("too many Quotas with name %s (found %d, expected 1)", d.Get("name").(string), numOfQuotas)
 	}
 
 	return nil
 }
+
+// flattenDataQuota maps a quota to the attributes of dataSourceQuotaSchema
+func flattenDataQuota(instance bmcapiclient.Quota) map[string]interface{} {
+	quotaItem := make(map[string]interface{})
+	quotaItem["id"] = instance.Id
+	quotaItem["name"] = instance.Name
+	quotaItem["description"] = instance.Description
+	quotaItem["status"] = instance.Status
+	quotaItem["limit"] = int(instance.Limit)
+	quotaItem["unit"] = instance.Unit
+	quotaItem["used"] = int(instance.Used)
+	quotaRequests := instance.QuotaEditLimitRequestDetails
+	qelrd := make([]interface{}, len(quotaRequests))
+	for i, j := range quotaRequests {
+		qelrdItem := make(map[string]interface{})
+		qelrdItem["limit"] = int(j.Limit)
+		qelrdItem["reason"] = j.Reason
+		qelrdItem["requested_on"] = j.RequestedOn.String()
+		qelrd[i] = qelrdItem
+	}
+	quotaItem["quota_edit_limit_request_details"] = qelrd
+	return quotaItem
+}
@@ -0,0 +1,55 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/quota"
+)
+
+func dataSourceQuotas() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceQuotasRead,
+
+		Schema: map[string]*schema.Schema{
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"quotas": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceQuotaSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceQuotasRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := quota.NewGetQuotasCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	quotas := make([]interface{}, 0)
+	for _, instance := range resp {
+		if v, ok := d.GetOk("status"); ok && instance.Status != v.(string) {
+			continue
+		}
+		quotas = append(quotas, flattenDataQuota(instance))
+	}
+	if err := d.Set("quotas", quotas); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
@@ -1,18 +1,18 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/ranchersolutionapi/cluster"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 )
 
 func dataSourceRancherCluster() *schema.Resource {
 	return &schema.Resource{
 
-		Read: dataSourceRancherClusterRead,
+		ReadContext: dataSourceRancherClusterRead,
 		Schema: map[string]*schema.Schema{
 			"id": {
 				Type:     schema.TypeString,
@@ -88,14 +88,14 @@ func dataSourceRancherCluster() *schema.Resource {
 	}
 }
 
-func dataSourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceRancherClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if len(d.Get("name").(string)) > 0 {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 
 		requestCommand := cluster.NewGetClustersCommand(client)
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		if len(d.Get("id").(string)) > 0 {
@@ -120,7 +120,7 @@ func dataSourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 							np := make([]interface{}, 0)
 							nodePools := flattenNodePools(instance.NodePools, np)
 							if err := d.Set("node_pools", nodePools); err != nil {
-								return err
+								return diag.FromErr(err)
 							}
 						}
 						if instance.Metadata != nil {
@@ -139,7 +139,7 @@ func dataSourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 				}
 			}
 			if numOfClusters > 1 {
-				return fmt.Errorf("too many clusters with id %s and name %s (found %d, expected 1)", d.Get("id").(string), d.Get("name").(string), numOfClusters)
+				return diag.Errorf("too many clusters with id %s and name %s (found %d, expected 1)", d.Get("id").(string), d.Get("name").(string), numOfClusters)
 			}
 		} else {
 			numOfClusters := 0
@@ -163,7 +163,7 @@ func dataSourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 							np := make([]interface{}, 0)
 							nodePools := flattenNodePools(instance.NodePools, np)
 							if err := d.Set("node_pools", nodePools); err != nil {
-								return err
+								return diag.FromErr(err)
 							}
 						}
 						if instance.Metadata != nil {
@@ -182,20 +182,20 @@ func dataSourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 				}
 			}
 			if numOfClusters > 1 {
-				return fmt.Errorf("too many clusters with name %s (found %d, expected 1)", d.Get("name").(string), numOfClusters)
+				return diag.Errorf("too many clusters with name %s (found %d, expected 1)", d.Get("name").(string), numOfClusters)
 			}
 		}
 
 	} else if len(d.Get("id").(string)) > 0 {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		clusterID := d.Get("id").(string)
 		requestCommand := cluster.NewGetClusterCommand(client, clusterID)
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		if resp.Id == nil {
-			return fmt.Errorf("unknown cluster identifier")
+			return diag.Errorf("unknown cluster identifier")
 		}
 		d.SetId(*resp.Id)
 		d.Set("id", *resp.Id)
@@ -213,7 +213,7 @@ func dataSourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 			np := make([]interface{}, 0)
 			nodePools := flattenNodePools(resp.NodePools, np)
 			if err := d.Set("node_pools", nodePools); err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 		if resp.Metadata != nil {
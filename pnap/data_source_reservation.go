@@ -1,145 +1,161 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/reservation"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	billingapiclient "github.com/phoenixnap/go-sdk-bmc/billingapi/v4"
 )
 
 func dataSourceReservation() *schema.Resource {
+	reservationSchema := dataSourceReservationSchema()
+	reservationSchema["id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+	reservationSchema["sku"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+	}
+
 	return &schema.Resource{
-		Read: dataSourceReservationRead,
+		ReadContext: dataSourceReservationRead,
+		Schema:      reservationSchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-			},
-			"product_code": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"product_category": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"location": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"reservation_model": { // Deprecated
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"term": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"lenght_in_months": {
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
-						"reservation_model": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+// dataSourceReservationSchema returns the computed attributes of a reservation.
+func dataSourceReservationSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"product_code": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"product_category": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"reservation_model": { // Deprecated
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"term": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"lenght_in_months": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+					"reservation_model": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"reservation_state": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"initial_invoice_model": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"quantity": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"quantity": {
-							Type:     schema.TypeFloat,
-							Computed: true,
-						},
-						"unit": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+		},
+		"reservation_state": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"initial_invoice_model": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"quantity": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"quantity": {
+						Type:     schema.TypeFloat,
+						Computed: true,
+					},
+					"unit": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"start_date_time": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"end_date_time": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"last_renewal_date_time": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"next_renewal_date_time": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"auto_renew": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"sku": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-			},
-			"price": {
-				Type:     schema.TypeFloat,
-				Computed: true,
-			},
-			"price_unit": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"assigned_resource_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"next_billing_date": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"utilization": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"quantity": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"quantity": {
-										Type:     schema.TypeFloat,
-										Computed: true,
-									},
-									"unit": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
+		},
+		"start_date_time": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"end_date_time": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"last_renewal_date_time": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"next_renewal_date_time": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"auto_renew": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"sku": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"price": {
+			Type:     schema.TypeFloat,
+			Computed: true,
+		},
+		"price_unit": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"assigned_resource_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"next_billing_date": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"utilization": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"quantity": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"quantity": {
+									Type:     schema.TypeFloat,
+									Computed: true,
+								},
+								"unit": {
+									Type:     schema.TypeString,
+									Computed: true,
 								},
 							},
 						},
-						"percentage": {
-							Type:     schema.TypeFloat,
-							Computed: true,
-						},
+					},
+					"percentage": {
+						Type:     schema.TypeFloat,
+						Computed: true,
 					},
 				},
 			},
@@ -147,12 +163,12 @@ func dataSourceReservation() *schema.Resource {
 	}
 }
 
-func dataSourceReservationRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceReservationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	requestCommand := reservation.NewGetReservationsCommand(client)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if len(d.Get("id").(string)) > 0 && len(d.Get("sku").(string)) > 0 {
 		numOfKeys := 0
@@ -160,49 +176,16 @@ func dataSourceReservationRead(d *schema.ResourceData, m interface{}) error {
 			if instance.Id == d.Get("id").(string) && instance.Sku == d.Get("sku").(string) {
 				numOfKeys++
 				d.SetId(instance.Id)
-				d.Set("id", instance.Id)
-				d.Set("product_code", instance.ProductCode)
-				d.Set("product_category", instance.ProductCategory)
-				d.Set("location", instance.Location)
-				d.Set("reservation_model", instance.ReservationModel)
-				term := flattenTerm(instance.Term)
-				d.Set("term", term)
-				d.Set("reservation_state", instance.ReservationState)
-				if instance.InitialInvoiceModel != nil {
-					d.Set("initial_invoice_model", *instance.InitialInvoiceModel)
-				}
-				quant := flattenQuantity(&instance.Quantity)
-				d.Set("quantity", quant)
-				d.Set("start_date_time", instance.StartDateTime.String())
-				if instance.EndDateTime != nil {
-					endDateTime := *instance.EndDateTime
-					d.Set("end_date_time", endDateTime.String())
-				}
-				if instance.LastRenewalDateTime != nil {
-					lastRenewalDateTime := *instance.LastRenewalDateTime
-					d.Set("last_renewal_date_time", lastRenewalDateTime.String())
+				for k, v := range flattenDataReservation(instance) {
+					if k == "id" {
+						continue
+					}
+					d.Set(k, v)
 				}
-				if instance.NextRenewalDateTime != nil {
-					nextRenewalDateTime := *instance.NextRenewalDateTime
-					d.Set("next_renewal_date_time", nextRenewalDateTime.String())
-				}
-				d.Set("auto_renew", instance.AutoRenew)
-				d.Set("sku", instance.Sku)
-				price := customRound(float64(instance.Price))
-				d.Set("price", price)
-				d.Set("price_unit", instance.PriceUnit)
-				if instance.AssignedResourceId != nil {
-					d.Set("assigned_resource_id", *instance.AssignedResourceId)
-				}
-				if instance.NextBillingDate != nil {
-					d.Set("next_billing_date", *instance.NextBillingDate)
-				}
-				util := flattenUtilization(instance.Utilization)
-				d.Set("utilization", util)
 			}
 		}
 		if numOfKeys > 1 {
-			return fmt.Errorf("too many reservations with id %s and sku %s (found %d, expected 1)", d.Get("id").(string), d.Get("sku").(string), numOfKeys)
+			return diag.Errorf("too many reservations with id %s and sku %s (found %d, expected 1)", d.Get("id").(string), d.Get("sku").(string), numOfKeys)
 		}
 	} else if len(d.Get("sku").(string)) > 0 {
 		numOfKeys := 0
@@ -210,49 +193,16 @@ func dataSourceReservationRead(d *schema.ResourceData, m interface{}) error {
 			if instance.Sku == d.Get("sku").(string) {
 				numOfKeys++
 				d.SetId(instance.Id)
-				d.Set("id", instance.Id)
-				d.Set("product_code", instance.ProductCode)
-				d.Set("product_category", instance.ProductCategory)
-				d.Set("location", instance.Location)
-				d.Set("reservation_model", instance.ReservationModel)
-				term := flattenTerm(instance.Term)
-				d.Set("term", term)
-				d.Set("reservation_state", instance.ReservationState)
-				if instance.InitialInvoiceModel != nil {
-					d.Set("initial_invoice_model", *instance.InitialInvoiceModel)
-				}
-				quant := flattenQuantity(&instance.Quantity)
-				d.Set("quantity", quant)
-				d.Set("start_date_time", instance.StartDateTime.String())
-				if instance.EndDateTime != nil {
-					endDateTime := *instance.EndDateTime
-					d.Set("end_date_time", endDateTime.String())
+				for k, v := range flattenDataReservation(instance) {
+					if k == "id" {
+						continue
+					}
+					d.Set(k, v)
 				}
-				if instance.LastRenewalDateTime != nil {
-					lastRenewalDateTime := *instance.LastRenewalDateTime
-					d.Set("last_renewal_date_time", lastRenewalDateTime.String())
-				}
-				if instance.NextRenewalDateTime != nil {
-					nextRenewalDateTime := *instance.NextRenewalDateTime
-					d.Set("next_renewal_date_time", nextRenewalDateTime.String())
-				}
-				d.Set("auto_renew", instance.AutoRenew)
-				d.Set("sku", instance.Sku)
-				price := customRound(float64(instance.Price))
-				d.Set("price", price)
-				d.Set("price_unit", instance.PriceUnit)
-				if instance.AssignedResourceId != nil {
-					d.Set("assigned_resource_id", *instance.AssignedResourceId)
-				}
-				if instance.NextBillingDate != nil {
-					d.Set("next_billing_date", *instance.NextBillingDate)
-				}
-				util := flattenUtilization(instance.Utilization)
-				d.Set("utilization", util)
 			}
 		}
 		if numOfKeys > 1 {
-			return fmt.Errorf("too many reservations with sku %s (found %d, expected 1)", d.Get("sku").(string), numOfKeys)
+			return diag.Errorf("too many reservations with sku %s (found %d, expected 1)", d.Get("sku").(string), numOfKeys)
 		}
 	} else {
 		numOfKeys := 0
@@ -260,50 +210,60 @@ func dataSourceReservationRead(d *schema.ResourceData, m interface{}) error {
 			if instance.Id == d.Get("id").(string) {
 				numOfKeys++
 				d.SetId(instance.Id)
-				d.Set("id", instance.Id)
-				d.Set("product_code", instance.ProductCode)
-				d.Set("product_category", instance.ProductCategory)
-				d.Set("location", instance.Location)
-				d.Set("reservation_model", instance.ReservationModel)
-				term := flattenTerm(instance.Term)
-				d.Set("term", term)
-				d.Set("reservation_state", instance.ReservationState)
-				if instance.InitialInvoiceModel != nil {
-					d.Set("initial_invoice_model", *instance.InitialInvoiceModel)
-				}
-				quant := flattenQuantity(&instance.Quantity)
-				d.Set("quantity", quant)
-				d.Set("start_date_time", instance.StartDateTime.String())
-				if instance.EndDateTime != nil {
-					endDateTime := *instance.EndDateTime
-					d.Set("end_date_time", endDateTime.String())
-				}
-				if instance.LastRenewalDateTime != nil {
-					lastRenewalDateTime := *instance.LastRenewalDateTime
-					d.Set("last_renewal_date_time", lastRenewalDateTime.String())
-				}
-				if instance.NextRenewalDateTime != nil {
-					nextRenewalDateTime := *instance.NextRenewalDateTime
-					d.Set("next_renewal_date_time", nextRenewalDateTime.String())
-				}
-				d.Set("auto_renew", instance.AutoRenew)
-				d.Set("sku", instance.Sku)
-				price := customRound(float64(instance.Price))
-				d.Set("price", price)
-				d.Set("price_unit", instance.PriceUnit)
-				if instance.AssignedResourceId != nil {
-					d.Set("assigned_resource_id", *instance.AssignedResourceId)
-				}
-				if instance.NextBillingDate != nil {
-					d.Set("next_billing_date", *instance.NextBillingDate)
+				for k, v := range flattenDataReservation(instance) {
+					if k == "id" {
+						continue
+					}
+					d.Set(k, v)
 				}
-				util := flattenUtilization(instance.Utilization)
-				d.Set("utilization", util)
 			}
 		}
 		if numOfKeys > 1 {
-			return fmt.Errorf("too many reservations with id %s (found %d, expected 1)", d.Get("id").(string), numOfKeys)
+			return diag.Errorf("too many reservations with id %s (found %d, expected 1)", d.Get("id").(string), numOfKeys)
 		}
 	}
 	return nil
 }
+
+// flattenDataReservation maps a reservation to the attributes of dataSourceReservationSchema
+func flattenDataReservation(instance billingapiclient.Reservation) map[string]interface{} {
+	reservationItem := make(map[string]interface{})
+	reservationItem["id"] = instance.Id
+	reservationItem["product_code"] = instance.ProductCode
+	reservationItem["product_category"] = string(instance.ProductCategory)
+	reservationItem["location"] = string(instance.Location)
+	reservationItem["reservation_model"] = string(instance.ReservationModel)
+	if instance.Term != nil {
+		reservationItem["term"] = flattenTerm(instance.Term)
+	}
+	reservationItem["reservation_state"] = string(instance.ReservationState)
+	if instance.InitialInvoiceModel != nil {
+		reservationItem["initial_invoice_model"] = string(*instance.InitialInvoiceModel)
+	}
+	reservationItem["quantity"] = flattenQuantity(&instance.Quantity)
+	reservationItem["start_date_time"] = instance.StartDateTime.String()
+	if instance.EndDateTime != nil {
+		endDateTime := *instance.EndDateTime
+		reservationItem["end_date_time"] = endDateTime.String()
+	}
+	if instance.LastRenewalDateTime != nil {
+		lastRenewalDateTime := *instance.LastRenewalDateTime
+		reservationItem["last_renewal_date_time"] = lastRenewalDateTime.String()
+	}
+	if instance.NextRenewalDateTime != nil {
+		nextRenewalDateTime := *instance.NextRenewalDateTime
+		reservationItem["next_renewal_date_time"] = nextRenewalDateTime.String()
+	}
+	reservationItem["auto_renew"] = instance.AutoRenew
+	reservationItem["sku"] = instance.Sku
+	reservationItem["price"] = customRound(float64(instance.Price))
+	reservationItem["price_unit"] = string(instance.PriceUnit)
+	if instance.AssignedResourceId != nil {
+		reservationItem["assigned_resource_id"] = *instance.AssignedResourceId
+	}
+	if instance.NextBillingDate != nil {
+		reservationItem["next_billing_date"] = *instance.NextBillingDate
+	}
+	reservationItem["utilization"] = flattenUtilization(instance.Utilization)
+	return reservationItem
+}
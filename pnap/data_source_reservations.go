@@ -0,0 +1,63 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	billingapiclient "github.com/phoenixnap/go-sdk-bmc/billingapi/v4"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/reservation"
+)
+
+func dataSourceReservations() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceReservationsRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"product_category": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"reservation_state": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"reservations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceReservationSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceReservationsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := reservation.NewGetReservationsCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filters := map[string]dataSourceFilter[billingapiclient.Reservation]{
+		"location":          equalsFilter(func(r billingapiclient.Reservation) string { return string(r.Location) }),
+		"product_category":  equalsFilter(func(r billingapiclient.Reservation) string { return string(r.ProductCategory) }),
+		"reservation_state": equalsFilter(func(r billingapiclient.Reservation) string { return string(r.ReservationState) }),
+	}
+	reservations := filterDataSourceItems(d, resp, filters, flattenDataReservation)
+	if err := d.Set("reservations", reservations); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
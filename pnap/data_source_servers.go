@@ -0,0 +1,94 @@
+package pnap
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+)
+
+func dataSourceServers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceServersRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"os": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tag_value": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"tag_name"},
+			},
+			"hostname_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"servers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceServerSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := server.NewGetServersCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var hostnameRegex *regexp.Regexp
+	if v, ok := d.GetOk("hostname_regex"); ok {
+		hostnameRegex = regexp.MustCompile(v.(string))
+	}
+	filters := map[string]dataSourceFilter[bmcapi.Server]{
+		"location": equalsFilter(func(s bmcapi.Server) string { return s.Location }),
+		"type":     equalsFilter(func(s bmcapi.Server) string { return s.Type }),
+		"os":       equalsFilter(func(s bmcapi.Server) string { return s.GetOs() }),
+		"status":   equalsFilter(func(s bmcapi.Server) string { return s.Status }),
+		"hostname_regex": func(s bmcapi.Server, _ interface{}) bool {
+			return hostnameRegex.MatchString(s.Hostname)
+		},
+		"tag_name": tagFilter(d,
+			func(s bmcapi.Server) []bmcapi.TagAssignment { return s.Tags },
+			func(t bmcapi.TagAssignment) (string, *string) { return t.Name, t.Value }),
+	}
+	servers := filterDataSourceItems(d, resp, filters, flattenDataServer)
+	if err := d.Set("servers", servers); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapServersDataSource_filters(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitServersDataSource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pnap_servers.all", "servers.#", "2"),
+					resource.TestCheckResourceAttr("data.pnap_servers.tagged", "servers.#", "1"),
+					resource.TestCheckResourceAttr("data.pnap_servers.tagged", "servers.0.hostname", rName+"-web"),
+					resource.TestCheckResourceAttr("data.pnap_servers.tagged", "servers.0.tags.0.value", "web"),
+					resource.TestCheckResourceAttr("data.pnap_servers.hostname", "servers.#", "1"),
+					resource.TestCheckResourceAttr("data.pnap_servers.hostname", "servers.0.hostname", rName+"-db"),
+					resource.TestCheckResourceAttr("data.pnap_servers.none", "servers.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testUnitServersDataSource(rName string) string {
+	return fmt.Sprintf(`
+resource "pnap_server" "web" {
+	hostname = "%s-web"
+	os = "ubuntu/jammy"
+	type = "s1.c1.medium"
+	location = "PHX"
+	tags {
+		tag_assignment {
+			name = "role"
+			value = "web"
+		}
+	}
+}
+
+resource "pnap_server" "db" {
+	hostname = "%s-db"
+	os = "ubuntu/jammy"
+	type = "s1.c1.medium"
+	location = "PHX"
+}
+
+data "pnap_servers" "all" {
+	location = "PHX"
+	depends_on = [pnap_server.web, pnap_server.db]
+}
+
+data "pnap_servers" "tagged" {
+	tag_name = "role"
+	tag_value = "web"
+	depends_on = [pnap_server.web, pnap_server.db]
+}
+
+data "pnap_servers" "hostname" {
+	hostname_regex = "-db$"
+	depends_on = [pnap_server.web, pnap_server.db]
+}
+
+data "pnap_servers" "none" {
+	location = "ASH"
+	depends_on = [pnap_server.web, pnap_server.db]
+}`, rName, rName)
+}
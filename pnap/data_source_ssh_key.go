@@ -1,54 +1,70 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/sshkey"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
 )
 
 func dataSourceSshKey() *schema.Resource {
+	sshKeySchema := dataSourceSshKeySchema()
+	sshKeySchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name"},
+	}
+	sshKeySchema["name"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
+
 	return &schema.Resource{
-		Read: dataSourceSshKeyRead,
+		ReadContext: dataSourceSshKeyRead,
+		Schema:      sshKeySchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"default": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"name"},
-			},
-			"name": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"key": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+// dataSourceSshKeySchema returns the computed attributes of an SSH key.
+func dataSourceSshKeySchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"default": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"key": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceSshKeyRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceSshKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	requestCommand := sshkey.NewGetSshKeysCommand(client)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	/* code := resp.StatusCode
 	if code != 200 {
 		response := &dto.ErrorMessage{}
 		response.FromBytes(resp)
-		return fmt.Errorf("API Returned Code from read method: %v, Message: %v, Validation Errors: %v", code, response.Message, response.ValidationErrors)
+		return diag.Errorf("API Returned Code from read method: %v, Message: %v, Validation Errors: %v", code, response.Message, response.ValidationErrors)
 	}
 	response := &dto.SshKeys{}
 	response.FromBytes(resp) */
@@ -58,15 +74,27 @@ func dataSourceSshKeyRead(d *schema.ResourceData, m interface{}) error {
 		if instance.Name == d.Get("name").(string) || instance.Id == d.Get("id").(string) {
 			numOfKeys++
 			d.SetId(instance.Id)
-			d.Set("default", instance.Default)
-			d.Set("name", instance.Name)
-			d.Set("key", instance.Key)
-
+			for k, v := range flattenDataSshKey(instance) {
+				if k == "id" {
+					continue
+				}
+				d.Set(k, v)
+			}
 		}
 	}
 	if numOfKeys > 1 {
-		return fmt.Errorf("too many ssh keys with name %s (found %d, expected 1)", d.Get("name").(string), numOfKeys)
+		return diag.Errorf("too many ssh keys with name %s (found %d, expected 1)", d.Get("name").(string), numOfKeys)
 	}
 
 	return nil
 }
+
+// flattenDataSshKey maps an SSH key to the attributes of dataSourceSshKeySchema
+func flattenDataSshKey(instance bmcapiclient.SshKey) map[string]interface{} {
+	sshKey := make(map[string]interface{})
+	sshKey["id"] = instance.Id
+	sshKey["default"] = instance.Default
+	sshKey["name"] = instance.Name
+	sshKey["key"] = instance.Key
+	return sshKey
+}
@@ -0,0 +1,55 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/sshkey"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceSshKeys() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceSshKeysRead,
+
+		Schema: map[string]*schema.Schema{
+			"default": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"ssh_keys": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceSshKeySchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSshKeysRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := sshkey.NewGetSshKeysCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	defaultFilter, filterByDefault := d.GetOkExists("default")
+	sshKeys := make([]interface{}, 0)
+	for _, instance := range resp {
+		if filterByDefault && instance.Default != defaultFilter.(bool) {
+			continue
+		}
+		sshKeys = append(sshKeys, flattenDataSshKey(instance))
+	}
+	if err := d.Set("ssh_keys", sshKeys); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
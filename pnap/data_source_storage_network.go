@@ -1,177 +1,167 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkstorageapi/storagenetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	networkstorageapiclient "github.com/phoenixnap/go-sdk-bmc/networkstorageapi/v3"
 )
 
 func dataSourceStorageNetwork() *schema.Resource {
-	return &schema.Resource{
+	storageNetworkSchema := dataSourceStorageNetworkSchema()
+	storageNetworkSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name"},
+	}
+	storageNetworkSchema["name"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
 
-		Read: dataSourceStorageNetworkRead,
+	return &schema.Resource{
+		ReadContext: dataSourceStorageNetworkRead,
+		Schema:      storageNetworkSchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"name"},
-			},
-			"name": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"location": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"description": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"network_id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"ips": {
-				Type:     schema.TypeSet,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"created_on": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"delete_requested_on": {
-				Type:     schema.TypeString,
-				Computed: true,
+// dataSourceStorageNetworkSchema returns the computed attributes of a storage network.
+func dataSourceStorageNetworkSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"location": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"network_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"ips": {
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"created_on": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"delete_requested_on": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"volumes": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: dataSourceVolumeSchema(),
 			},
-			"volumes": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"name": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"description": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"path_suffix": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"capacity_in_gb": {
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
-						"used_capacity_in_gb": {
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
-						"path": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"protocol": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"status": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"created_on": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"delete_requested_on": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"permissions": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"nfs": {
-										Type:     schema.TypeList,
-										Computed: true,
-										Elem: &schema.Resource{
-											Schema: map[string]*schema.Schema{
-												"read_write": {
-													Type:     schema.TypeSet,
-													Computed: true,
-													Elem:     &schema.Schema{Type: schema.TypeString},
-												},
-												"read_only": {
-													Type:     schema.TypeSet,
-													Computed: true,
-													Elem:     &schema.Schema{Type: schema.TypeString},
-												},
-												"root_squash": {
-													Type:     schema.TypeSet,
-													Computed: true,
-													Elem:     &schema.Schema{Type: schema.TypeString},
-												},
-												"no_squash": {
-													Type:     schema.TypeSet,
-													Computed: true,
-													Elem:     &schema.Schema{Type: schema.TypeString},
-												},
-												"all_squash": {
-													Type:     schema.TypeSet,
-													Computed: true,
-													Elem:     &schema.Schema{Type: schema.TypeString},
-												},
-											},
-										},
-									},
+		},
+	}
+}
+
+// dataSourceVolumeSchema returns the computed attributes of a storage network volume.
+func dataSourceVolumeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"path_suffix": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"capacity_in_gb": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"used_capacity_in_gb": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"path": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"protocol": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"status": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"created_on": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"delete_requested_on": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"permissions": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"nfs": {
+						Type:     schema.TypeList,
+						Computed: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"read_write": {
+									Type:     schema.TypeSet,
+									Computed: true,
+									Elem:     &schema.Schema{Type: schema.TypeString},
 								},
-							},
-						},
-						"tags": {
-							Type:     schema.TypeList,
-							Computed: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"id": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
-									"name": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
-									"value": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
-									"is_billing_tag": {
-										Type:     schema.TypeBool,
-										Computed: true,
-									},
-									"created_by": {
-										Type:     schema.TypeString,
-										Computed: true,
-									},
+								"read_only": {
+									Type:     schema.TypeSet,
+									Computed: true,
+									Elem:     &schema.Schema{Type: schema.TypeString},
+								},
+								"root_squash": {
+									Type:     schema.TypeSet,
+									Computed: true,
+									Elem:     &schema.Schema{Type: schema.TypeString},
+								},
+								"no_squash": {
+									Type:     schema.TypeSet,
+									Computed: true,
+									Elem:     &schema.Schema{Type: schema.TypeString},
+								},
+								"all_squash": {
+									Type:     schema.TypeSet,
+									Computed: true,
+									Elem:     &schema.Schema{Type: schema.TypeString},
 								},
 							},
 						},
@@ -179,15 +169,43 @@ func dataSourceStorageNetwork() *schema.Resource {
 				},
 			},
 		},
+		"tags": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"is_billing_tag": {
+						Type:     schema.TypeBool,
+						Computed: true,
+					},
+					"created_by": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
 	}
 }
 
-func dataSourceStorageNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceStorageNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	requestCommand := storagenetwork.NewGetStorageNetworksCommand(client)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	numOfStorageNets := 0
@@ -195,45 +213,60 @@ func dataSourceStorageNetworkRead(d *schema.ResourceData, m interface{}) error {
 		if instance.Name != nil && *instance.Name == d.Get("name").(string) || instance.Id != nil && *instance.Id == d.Get("id").(string) {
 			numOfStorageNets++
 			d.SetId(*instance.Id)
-			d.Set("name", *instance.Name)
-			if instance.Description != nil {
-				d.Set("description", *instance.Description)
-			}
-			if instance.Status != nil {
-				d.Set("status", *instance.Status)
-			}
-			if instance.Location != nil {
-				d.Set("location", *instance.Location)
-			}
-			if instance.NetworkId != nil {
-				d.Set("network_id", *instance.NetworkId)
-			}
-			var ips []interface{}
-			for _, v := range instance.Ips {
-				ips = append(ips, v)
-			}
-			d.Set("ips", ips)
-			if instance.CreatedOn != nil {
-				createdOn := *instance.CreatedOn
-				d.Set("created_on", createdOn.String())
-			}
-			if instance.DeleteRequestedOn != nil {
-				delReqOn := *instance.DeleteRequestedOn
-				d.Set("delete_requested_on", delReqOn.String())
-			}
-			volumes := flattenDataVolumes(instance.Volumes)
-
-			if err := d.Set("volumes", volumes); err != nil {
-				return err
+			for k, v := range flattenDataStorageNetwork(instance) {
+				if k == "id" {
+					continue
+				}
+				if err := d.Set(k, v); err != nil {
+					return diag.FromErr(err)
+				}
 			}
 		}
 	}
 	if numOfStorageNets > 1 {
-		return fmt.Errorf("too many storage networks with name %s (found %d, expected 1)", d.Get("name").(string), numOfStorageNets)
+		return diag.Errorf("too many storage networks with name %s (found %d, expected 1)", d.Get("name").(string), numOfStorageNets)
 	}
 	return nil
 }
 
+// flattenDataStorageNetwork maps a storage network to the attributes of dataSourceStorageNetworkSchema
+func flattenDataStorageNetwork(instance networkstorageapiclient.StorageNetwork) map[string]interface{} {
+	storageNetwork := make(map[string]interface{})
+	if instance.Id != nil {
+		storageNetwork["id"] = *instance.Id
+	}
+	if instance.Name != nil {
+		storageNetwork["name"] = *instance.Name
+	}
+	if instance.Description != nil {
+		storageNetwork["description"] = *instance.Description
+	}
+	if instance.Status != nil {
+		storageNetwork["status"] = string(*instance.Status)
+	}
+	if instance.Location != nil {
+		storageNetwork["location"] = *instance.Location
+	}
+	if instance.NetworkId != nil {
+		storageNetwork["network_id"] = *instance.NetworkId
+	}
+	var ips []interface{}
+	for _, v := range instance.Ips {
+		ips = append(ips, v)
+	}
+	storageNetwork["ips"] = ips
+	if instance.CreatedOn != nil {
+		createdOn := *instance.CreatedOn
+		storageNetwork["created_on"] = createdOn.String()
+	}
+	if instance.DeleteRequestedOn != nil {
+		delReqOn := *instance.DeleteRequestedOn
+		storageNetwork["delete_requested_on"] = delReqOn.String()
+	}
+	storageNetwork["volumes"] = flattenDataVolumes(instance.Volumes)
+	return storageNetwork
+}
+
 func flattenDataVolumes(volumes []networkstorageapiclient.Volume) []interface{} {
 	if volumes != nil {
 		vols := make([]interface{}, len(volumes))
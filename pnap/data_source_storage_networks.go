@@ -0,0 +1,62 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkstorageapi/storagenetwork"
+)
+
+func dataSourceStorageNetworks() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceStorageNetworksRead,
+
+		Schema: map[string]*schema.Schema{
+			"location": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"storage_networks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceStorageNetworkSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceStorageNetworksRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := storagenetwork.NewGetStorageNetworksCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	storageNetworks := make([]interface{}, 0)
+	for _, instance := range resp {
+		if v, ok := d.GetOk("location"); ok && (instance.Location == nil || *instance.Location != v.(string)) {
+			continue
+		}
+		if v, ok := d.GetOk("status"); ok && (instance.Status == nil || string(*instance.Status) != v.(string)) {
+			continue
+		}
+		storageNetworks = append(storageNetworks, flattenDataStorageNetwork(instance))
+	}
+	if err := d.Set("storage_networks", storageNetworks); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package pnap
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkstorageapi/storagenetwork"
+	networkstorageapiclient "github.com/phoenixnap/go-sdk-bmc/networkstorageapi/v3"
+)
+
+func dataSourceStorageVolume() *schema.Resource {
+	volumeSchema := dataSourceVolumeSchema()
+	volumeSchema["storage_network_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	volumeSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name"},
+	}
+	volumeSchema["name"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
+
+	return &schema.Resource{
+
+		ReadContext: dataSourceStorageVolumeRead,
+
+		Schema: volumeSchema,
+	}
+}
+
+func dataSourceStorageVolumeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := storagenetwork.NewGetStorageNetworkCommand(client, d.Get("storage_network_id").(string))
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var matches []networkstorageapiclient.Volume
+	for _, instance := range resp.Volumes {
+		if instance.Name != nil && *instance.Name == d.Get("name").(string) || instance.Id != nil && *instance.Id == d.Get("id").(string) {
+			matches = append(matches, instance)
+		}
+	}
+	if len(matches) > 1 {
+		return diag.Errorf("too many volumes with name %s (found %d, expected 1)", d.Get("name").(string), len(matches))
+	}
+	if len(matches) == 0 {
+		return diag.Errorf("volume not found")
+	}
+
+	d.SetId(*matches[0].Id)
+	volume := flattenDataVolumes(matches)[0].(map[string]interface{})
+	for k, v := range volume {
+		if k == "id" {
+			continue
+		}
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkstorageapi/storagenetwork"
+)
+
+func dataSourceStorageVolumes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceStorageVolumesRead,
+
+		Schema: map[string]*schema.Schema{
+			"storage_network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"volumes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceVolumeSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceStorageVolumesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := storagenetwork.NewGetStorageNetworkCommand(client, d.Get("storage_network_id").(string))
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("volumes", flattenDataVolumes(resp.Volumes)); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
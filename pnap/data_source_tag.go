@@ -1,112 +1,139 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/tagapi/tag"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	tagapiclient "github.com/phoenixnap/go-sdk-bmc/tagapi/v3"
 )
 
 func dataSourceTag() *schema.Resource {
+	tagSchema := dataSourceTagSchema()
+	tagSchema["id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"name"},
+	}
+	tagSchema["name"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"id"},
+	}
+
 	return &schema.Resource{
-		Read: dataSourceTagRead,
+		ReadContext: dataSourceTagRead,
+		Schema:      tagSchema,
+	}
+}
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"name"},
-			},
-			"name": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				Computed:      true,
-				ConflictsWith: []string{"id"},
-			},
-			"values": {
-				Type:     schema.TypeSet,
-				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-			},
-			"description": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"is_billing_tag": {
-				Type:     schema.TypeBool,
-				Computed: true,
-			},
-			"resource_assignments": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"resource_name": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"value": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+// dataSourceTagSchema returns the computed attributes of a tag.
+func dataSourceTagSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"values": {
+			Type:     schema.TypeSet,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"is_billing_tag": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"resource_assignments": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"resource_name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"created_by": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
+		},
+		"created_by": {
+			Type:     schema.TypeString,
+			Computed: true,
 		},
 	}
 }
 
-func dataSourceTagRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceTagRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	requestCommand := tag.NewGetTagsCommand(client)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	numOfTags := 0
 	for _, instance := range resp {
 		if instance.Name == d.Get("name").(string) || instance.Id == d.Get("id").(string) {
 			numOfTags++
 			d.SetId(instance.Id)
-			d.Set("name", instance.Name)
-			if instance.Values != nil {
-				readValues := instance.Values
-				var values []interface{}
-				for _, v := range readValues {
-					values = append(values, v)
-				}
-				d.Set("values", values)
-			}
-			if instance.Description != nil {
-				d.Set("description", *instance.Description)
-			}
-			d.Set("is_billing_tag", instance.IsBillingTag)
-			if instance.ResourceAssignments != nil {
-				readAssigns := instance.ResourceAssignments
-				assigns := make([]interface{}, len(readAssigns))
-				for i, a := range readAssigns {
-					assign := make(map[string]interface{})
-					assign["resource_name"] = a.ResourceName
-					if a.Value != nil {
-						assign["value"] = *a.Value
-					}
-					assigns[i] = assign
+			for k, v := range flattenDataTag(instance) {
+				if k == "id" {
+					continue
 				}
-				d.Set("resource_assignments", assigns)
-			}
-			if instance.CreatedBy != nil {
-				d.Set("created_by", *instance.CreatedBy)
+				d.Set(k, v)
 			}
 		}
 	}
 	if numOfTags > 1 {
-		return fmt.Errorf("too many tags with name %s (found %d, expected 1)", d.Get("name").(string), numOfTags)
+		return diag.Errorf("too many tags with name %s (found %d, expected 1)", d.Get("name").(string), numOfTags)
 	}
 	return nil
 }
+
+// flattenDataTag maps a tag to the attributes of dataSourceTagSchema
+func flattenDataTag(instance tagapiclient.Tag) map[string]interface{} {
+	tagItem := make(map[string]interface{})
+	tagItem["id"] = instance.Id
+	tagItem["name"] = instance.Name
+	if instance.Values != nil {
+		var values []interface{}
+		for _, v := range instance.Values {
+			values = append(values, v)
+		}
+		tagItem["values"] = values
+	}
+	if instance.Description != nil {
+		tagItem["description"] = *instance.Description
+	}
+	tagItem["is_billing_tag"] = instance.IsBillingTag
+	if instance.ResourceAssignments != nil {
+		assigns := make([]interface{}, len(instance.ResourceAssignments))
+		for i, a := range instance.ResourceAssignments {
+			assign := make(map[string]interface{})
+			assign["resource_name"] = a.ResourceName
+			if a.Value != nil {
+				assign["value"] = *a.Value
+			}
+			assigns[i] = assign
+		}
+		tagItem["resource_assignments"] = assigns
+	}
+	if instance.CreatedBy != nil {
+		tagItem["created_by"] = *instance.CreatedBy
+	}
+	return tagItem
+}
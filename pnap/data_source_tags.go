@@ -0,0 +1,53 @@
+package pnap
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/tagapi/tag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	tagapiclient "github.com/phoenixnap/go-sdk-bmc/tagapi/v3"
+)
+
+func dataSourceTags() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceTagsRead,
+
+		Schema: map[string]*schema.Schema{
+			"is_billing_tag": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceTagSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTagsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	requestCommand := tag.NewGetTagsCommand(client)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	filters := map[string]dataSourceFilter[tagapiclient.Tag]{
+		"is_billing_tag": func(t tagapiclient.Tag, value interface{}) bool { return t.IsBillingTag == value.(bool) },
+	}
+	tags := filterDataSourceItems(d, resp, filters, flattenDataTag)
+	if err := d.Set("tags", tags); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+
+	return nil
+}
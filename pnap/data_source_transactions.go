@@ -1,20 +1,20 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
 	"math"
 	"strconv"
 	"time"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/paymentsapi/transaction"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceTransactions() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceTransactionsRead,
+		ReadContext: dataSourceTransactionsRead,
 
 		Schema: map[string]*schema.Schema{
 			"limit": {
@@ -137,8 +137,8 @@ func dataSourceTransactions() *schema.Resource {
 	}
 }
 
-func dataSourceTransactionsRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func dataSourceTransactionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	query := dto.Query{}
 	query.Limit = int32(d.Get("limit").(int))
@@ -150,7 +150,7 @@ func dataSourceTransactionsRead(d *schema.ResourceData, m interface{}) error {
 	if from != "" {
 		t1, err1 := time.Parse(time.RFC3339, from)
 		if err1 != nil {
-			return err1
+			return diag.FromErr(err1)
 		} else {
 			query.From = t1
 		}
@@ -159,16 +159,16 @@ func dataSourceTransactionsRead(d *schema.ResourceData, m interface{}) error {
 	if to != "" {
 		t2, err2 := time.Parse(time.RFC3339, to)
 		if err2 != nil {
-			return err2
+			return diag.FromErr(err2)
 		} else {
 			query.To = t2
 		}
 	}
 
 	requestCommand := transaction.NewGetTransactionsCommand(client, query)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	paginatedTransactions := make([]interface{}, 1)
@@ -224,7 +224,7 @@ func dataSourceTransactionsRead(d *schema.ResourceData, m interface{}) error {
 			}
 		}
 		if numOfTransactions > 1 {
-			return fmt.Errorf("too many transactions with id %s (found %d, expected 1)", id, numOfTransactions)
+			return diag.Errorf("too many transactions with id %s (found %d, expected 1)", id, numOfTransactions)
 		}
 
 	} else {
@@ -0,0 +1,124 @@
+package pnap
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// defaultTag is a tag from the default_tags provider block, it is assigned to every taggable resource.
+type defaultTag struct {
+	name  string
+	value string
+}
+
+func expandDefaultTags(defaultTags []interface{}) []defaultTag {
+	var tags []defaultTag
+	for _, j := range defaultTags {
+		tagItem := j.(map[string]interface{})
+		tags = append(tags, defaultTag{name: tagItem["name"].(string), value: tagItem["value"].(string)})
+	}
+	return tags
+}
+
+// isDefaultTag reports whether a tag of the given name comes from the default_tags provider block.
+func isDefaultTag(name string, defaultTags []defaultTag) bool {
+	for _, t := range defaultTags {
+		if t.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyDefaultTags reports whether every one of the given tag names comes from the default_tags provider block.
+func onlyDefaultTags(names []string, defaultTags []defaultTag) bool {
+	for _, name := range names {
+		if !isDefaultTag(name, defaultTags) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagAssignment is implemented by the tag assignments, and the requests to set them, of every API.
+type tagAssignment[T any] interface {
+	*T
+	GetName() string
+	SetName(string)
+	GetValue() string
+	SetValue(string)
+}
+
+// mergeDefaultTags appends the default tags that aren't assigned on the resource itself, resource level values win.
+func mergeDefaultTags[T any, PT tagAssignment[T]](tags []T, defaultTags []defaultTag) []T {
+	assigned := make(map[string]bool)
+	for i := range tags {
+		assigned[PT(&tags[i]).GetName()] = true
+	}
+	for _, t := range defaultTags {
+		if assigned[t.name] {
+			continue
+		}
+		var tag T
+		PT(&tag).SetName(t.name)
+		if len(t.value) > 0 {
+			PT(&tag).SetValue(t.value)
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// tagsAllSchema is the tags_all attribute of the taggable resources.
+func tagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// flattenTagsAll returns the tags assigned to a resource as read from the API, for tags_all.
+func flattenTagsAll[T any, PT tagAssignment[T]](tags []T) map[string]interface{} {
+	tagsAll := make(map[string]interface{}, len(tags))
+	for i := range tags {
+		tagsAll[PT(&tags[i]).GetName()] = PT(&tags[i]).GetValue()
+	}
+	return tagsAll
+}
+
+// expectedTagsAll returns the configured tag assignments merged with the default tags, as they end up on the resource.
+func expectedTagsAll(tags []interface{}, defaultTags []defaultTag) map[string]interface{} {
+	tagsAll := make(map[string]interface{})
+	for _, t := range defaultTags {
+		tagsAll[t.name] = t.value
+	}
+	for _, j := range tags {
+		tagsItem, _ := j.(map[string]interface{})
+		if tagsItem == nil || tagsItem["tag_assignment"] == nil || len(tagsItem["tag_assignment"].([]interface{})) == 0 {
+			continue
+		}
+		tagAssignItem, _ := tagsItem["tag_assignment"].([]interface{})[0].(map[string]interface{})
+		if tagAssignItem == nil {
+			continue
+		}
+		value, _ := tagAssignItem["value"].(string)
+		tagsAll[tagAssignItem["name"].(string)] = value
+	}
+	return tagsAll
+}
+
+// customizeDiffTagsAll plans tags_all from the configured tags and the default_tags provider block, so that changing
+// default_tags updates the tags of the resources already created.
+func customizeDiffTagsAll(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("tags") {
+		return d.SetNewComputed("tags_all")
+	}
+	expected := expectedTagsAll(d.Get("tags").([]interface{}), m.(*providerMeta).defaultTags)
+	if reflect.DeepEqual(d.Get("tags_all").(map[string]interface{}), expected) {
+		return nil
+	}
+	return d.SetNew("tags_all", expected)
+}
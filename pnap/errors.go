@@ -0,0 +1,29 @@
+package pnap
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// openAPIError is satisfied by the GenericOpenAPIError type of every go-sdk-bmc API package.
+type openAPIError interface {
+	error
+	Body() []byte
+	Model() interface{}
+}
+
+// apiReturnedCodeNotFound matches the message the SDK helper builds out of non 2xx responses.
+var apiReturnedCodeNotFound = regexp.MustCompile(`API Returned Code( from [a-z ]+)?:? 404\b`)
+
+// isNotFound reports whether err is the API stating that the requested object doesn't exist.
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr openAPIError
+	if errors.As(err, &apiErr) && strings.HasPrefix(apiErr.Error(), "404") {
+		return true
+	}
+	return apiReturnedCodeNotFound.MatchString(err.Error())
+}
@@ -0,0 +1,159 @@
+package pnap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/logging"
+)
+
+const (
+	redacted = "[REDACTED]"
+	// maxLoggedBody truncates large bodies, e.g. listings of many servers.
+	maxLoggedBody = 64 * 1024
+)
+
+// sensitiveFields are the lower-cased names of JSON properties and form fields whose values are never
+// logged: credentials and tokens, server and cluster passwords, BGP passwords and cloud-init user data.
+var sensitiveFields = map[string]bool{
+	"client_secret":      true,
+	"clientsecret":       true,
+	"access_token":       true,
+	"accesstoken":        true,
+	"refresh_token":      true,
+	"id_token":           true,
+	"password":           true,
+	"rootpassword":       true,
+	"netrisuserpassword": true,
+	"userdata":           true,
+}
+
+// sensitiveText matches sensitive values in bodies that can't be parsed, as JSON properties, form fields
+// or bearer tokens.
+var sensitiveText = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)("(?:client_?secret|access_?token|refresh_token|id_token|password|root_?password|netris_?user_?password|user_?data)"\s*:\s*")(?:[^"\\]|\\.)*`),
+	regexp.MustCompile(`(?i)(\b(?:client_secret|access_token|refresh_token|id_token|password)=)[^&\s]*`),
+	regexp.MustCompile(`(?i)(\bBearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+}
+
+// httpLogger logs the requests to the API along with their responses when TF_LOG is DEBUG or TRACE.
+// Headers aren't logged, as they carry the bearer token, and sensitiveFields are redacted from bodies.
+type httpLogger struct {
+	base http.RoundTripper
+}
+
+func (l *httpLogger) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !logging.IsDebugOrHigher() {
+		return l.base.RoundTrip(req)
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		log.Printf("[DEBUG] pnap API request: %s %s\n%s", req.Method, req.URL.Redacted(), redactBody(req.Header.Get("Content-Type"), body))
+	} else {
+		log.Printf("[DEBUG] pnap API request: %s %s", req.Method, req.URL.Redacted())
+	}
+
+	start := time.Now()
+	resp, err := l.base.RoundTrip(req)
+	latency := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		log.Printf("[DEBUG] pnap API request failed: %s %s after %s: %v", req.Method, req.URL.Redacted(), latency, err)
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	log.Printf("[DEBUG] pnap API response: %s %s: %s in %s\n%s", req.Method, req.URL.Redacted(), resp.Status, latency, redactBody(resp.Header.Get("Content-Type"), body))
+	return resp, nil
+}
+
+// redactBody returns a body fit for the logs, with the values of sensitiveFields replaced.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Sprintf("<%d bytes of unparsable form data>", len(body))
+		}
+		for k := range values {
+			if sensitiveFields[strings.ToLower(k)] {
+				values.Set(k, redacted)
+			}
+		}
+		// Unescaped to keep the logs readable, the fields can't be parsed back from it.
+		if decoded, err := url.QueryUnescape(values.Encode()); err == nil {
+			return decoded
+		}
+		return values.Encode()
+	case strings.HasSuffix(mediaType, "json") || json.Valid(body):
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return truncateBody(redactText(string(body)))
+		}
+		out, _ := json.MarshalIndent(redactValue(value), "", " ")
+		return truncateBody(string(out))
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "":
+		return truncateBody(redactText(string(body)))
+	default:
+		return fmt.Sprintf("<%d bytes of %s>", len(body), mediaType)
+	}
+}
+
+// redactValue replaces the values of sensitiveFields anywhere in a decoded JSON document.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if sensitiveFields[strings.ToLower(k)] && field != nil {
+				v[k] = redacted
+			} else {
+				v[k] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// redactText replaces the values matched by sensitiveText.
+func redactText(body string) string {
+	for _, re := range sensitiveText {
+		body = re.ReplaceAllString(body, "${1}"+redacted)
+	}
+	return body
+}
+
+func truncateBody(body string) string {
+	if len(body) <= maxLoggedBody {
+		return body
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", body[:maxLoggedBody], len(body)-maxLoggedBody)
+}
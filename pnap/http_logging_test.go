@@ -0,0 +1,92 @@
+package pnap
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	for _, v := range []struct {
+		name, contentType, body string
+		secrets                 []string
+	}{
+		{"server", "application/json", `{"hostname":"web","password":"s3cret-pw","rootPassword":"r00t-pw","netrisUserPassword":"n3tris-pw","osConfiguration":{"cloudInit":{"userData":"I2Nsb3VkLWNvbmZpZw=="}}}`,
+			[]string{"s3cret-pw", "r00t-pw", "n3tris-pw", "I2Nsb3VkLWNvbmZpZw=="}},
+		{"rancher cluster", "application/json; charset=utf-8", `[{"name":"cluster","metadata":{"username":"admin","password":"r4ncher-pw"}}]`,
+			[]string{"r4ncher-pw"}},
+		{"bgp peer group", "application/json", `{"location":"PHX","password":"bgp-pw","asn":65401}`,
+			[]string{"bgp-pw"}},
+		{"token request", "application/x-www-form-urlencoded", "client_id=mock-client-id&client_secret=mock-client-secret&grant_type=client_credentials",
+			[]string{"mock-client-secret"}},
+		{"token response", "application/json", `{"access_token":"eyJ0eXAi.eyJzdWIi.c2ln","token_type":"Bearer","expires_in":300}`,
+			[]string{"eyJ0eXAi.eyJzdWIi.c2ln"}},
+		{"truncated json", "application/json", `{"hostname":"web","rootPassword":"r00t-pw","descr`,
+			[]string{"r00t-pw"}},
+		{"text", "text/plain", "invalid token: Bearer abc.def.ghi",
+			[]string{"abc.def.ghi"}},
+	} {
+		logged := redactBody(v.contentType, []byte(v.body))
+		for _, secret := range v.secrets {
+			if strings.Contains(logged, secret) {
+				t.Errorf("%s: %q was logged: %s", v.name, secret, logged)
+			}
+		}
+		if !strings.Contains(logged, redacted) {
+			t.Errorf("%s: nothing was redacted: %s", v.name, logged)
+		}
+	}
+	if logged := redactBody("application/json", []byte(`{"hostname":"web","asn":65401}`)); !strings.Contains(logged, `"hostname": "web"`) || !strings.Contains(logged, "65401") {
+		t.Errorf("non sensitive fields weren't logged: %s", logged)
+	}
+	if logged := redactBody("application/pdf", []byte("%PDF-1.4")); logged != "<8 bytes of application/pdf>" {
+		t.Errorf("binary body was logged: %s", logged)
+	}
+}
+
+func TestHttpLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "r00t-pw") {
+			t.Errorf("the request body wasn't forwarded: %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"server-1","rootPassword":"r00t-pw"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(io.Discard)
+	t.Setenv("TF_LOG", "DEBUG")
+
+	client := &http.Client{Transport: &httpLogger{base: http.DefaultTransport}}
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/bmc/v1/servers", strings.NewReader(`{"hostname":"web","rootPassword":"r00t-pw"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+mockAccessToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "r00t-pw") {
+		t.Errorf("the response body wasn't passed on: %s", body)
+	}
+
+	logged := output.String()
+	for _, expected := range []string{"[DEBUG] pnap API request: POST " + server.URL + "/bmc/v1/servers", "200 OK in", `"id": "server-1"`} {
+		if !strings.Contains(logged, expected) {
+			t.Errorf("%q wasn't logged: %s", expected, logged)
+		}
+	}
+	for _, secret := range []string{"r00t-pw", mockAccessToken} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("%q was logged: %s", secret, logged)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package pnap
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// requestLimiter caps the API requests of the provider, shared by every resource, data source and waiter.
+// A nil requestLimiter doesn't limit anything.
+type requestLimiter struct {
+	// slots bounds the requests in flight, nil if their number isn't limited
+	slots chan struct{}
+
+	// rate and burst configure the token bucket, a rate of 0 doesn't limit the requests per second
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newRequestLimiter returns nil when neither the concurrency nor the rate is limited.
+func newRequestLimiter(maxConcurrent int, perSecond float64) *requestLimiter {
+	if maxConcurrent <= 0 && perSecond <= 0 {
+		return nil
+	}
+	l := &requestLimiter{rate: perSecond}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if perSecond > 0 {
+		l.burst = math.Max(1, math.Floor(perSecond))
+		l.tokens = l.burst
+		l.last = time.Now()
+	}
+	return l
+}
+
+// acquire waits for a token and a free slot. The returned function frees the slot once the request is done.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	if delay := l.reserve(time.Now()); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	}
+}
+
+// reserve takes a token from the bucket and returns how long to wait until it is available. Tokens are
+// handed out in advance, so concurrent callers line up behind each other.
+func (l *requestLimiter) reserve(now time.Time) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
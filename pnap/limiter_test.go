@@ -0,0 +1,85 @@
+package pnap
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestLimiterReserve(t *testing.T) {
+	l := newRequestLimiter(0, 2)
+	now := l.last
+	for i, expected := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if delay := l.reserve(now); delay != expected {
+			t.Errorf("reserve() #%d = %s, expected %s", i+1, delay, expected)
+		}
+	}
+	// the bucket refills at the configured rate but never above its burst
+	if delay := l.reserve(now.Add(10 * time.Second)); delay != 0 {
+		t.Errorf("reserve() after a pause = %s, expected 0", delay)
+	}
+	if delay := l.reserve(now.Add(10 * time.Second)); delay != 0 {
+		t.Errorf("reserve() within the burst = %s, expected 0", delay)
+	}
+	if delay := l.reserve(now.Add(10 * time.Second)); delay != 500*time.Millisecond {
+		t.Errorf("reserve() past the burst = %s, expected 500ms", delay)
+	}
+}
+
+func TestRequestLimiterConcurrency(t *testing.T) {
+	l := newRequestLimiter(2, 0)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	inFlight, peak := 0, 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.acquire(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer release()
+			mu.Lock()
+			inFlight++
+			peak = max(peak, inFlight)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if peak != 2 {
+		t.Errorf("%d requests were in flight at once, expected 2", peak)
+	}
+}
+
+func TestRequestLimiterCancel(t *testing.T) {
+	l := newRequestLimiter(1, 0)
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); err == nil {
+		t.Error("acquire() returned without a free slot")
+	}
+}
+
+func TestRequestLimiterUnlimited(t *testing.T) {
+	if l := newRequestLimiter(0, 0); l != nil {
+		t.Errorf("newRequestLimiter(0, 0) = %v, expected nil", l)
+	}
+	var l *requestLimiter
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
@@ -0,0 +1,1569 @@
+package pnap
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	billingapiclient "github.com/phoenixnap/go-sdk-bmc/billingapi/v4"
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+	ipapiclient "github.com/phoenixnap/go-sdk-bmc/ipapi/v3"
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
+	networkstorageapiclient "github.com/phoenixnap/go-sdk-bmc/networkstorageapi/v3"
+	rancherapiclient "github.com/phoenixnap/go-sdk-bmc/ranchersolutionapi/v3"
+	tagapiclient "github.com/phoenixnap/go-sdk-bmc/tagapi/v3"
+)
+
+func mockNow() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// BMC API
+
+func (m *mockAPI) serveBmc(w http.ResponseWriter, r *http.Request, path []string) {
+	switch path[0] {
+	case "servers":
+		m.serveServers(w, r, path[1:])
+	case "ssh-keys":
+		m.serveSshKeys(w, r, path[1:])
+	case "quotas":
+		m.serveQuotas(w, r, path[1:])
+	default:
+		mockNotFound(w)
+	}
+}
+
+func (m *mockAPI) serveServers(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			servers := []bmcapiclient.Server{}
+			for _, s := range m.servers {
+				servers = append(servers, *s)
+			}
+			mockJSON(w, http.StatusOK, servers)
+		case http.MethodPost:
+			m.createServer(w, r)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	id := path[0]
+	if r.Method == http.MethodGet && len(path) == 1 {
+		m.poll(id)
+		for k := range m.transitions {
+			if strings.HasPrefix(k, id+"/") {
+				m.poll(k)
+			}
+		}
+	}
+	s, ok := m.servers[id]
+	if !ok {
+		mockNotFound(w)
+		return
+	}
+
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+		mockJSON(w, http.StatusOK, s)
+	case len(path) == 1 && r.Method == http.MethodPatch:
+		var req bmcapiclient.ServerPatch
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Hostname != nil {
+			s.Hostname = *req.Hostname
+		}
+		if req.Description != nil {
+			s.Description = req.Description
+		}
+		mockJSON(w, http.StatusOK, s)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		m.releaseServer(s, false)
+		delete(m.servers, id)
+		mockJSON(w, http.StatusOK, bmcapiclient.DeleteResult{Result: "Server deleted", ServerId: id})
+	case len(path) == 2 && path[1] == "tags" && r.Method == http.MethodPut:
+		var req []bmcapiclient.TagAssignmentRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		s.Tags = m.serverTags(req)
+		mockJSON(w, http.StatusOK, s)
+	case len(path) == 3 && path[1] == "os-configuration" && path[2] == "ipxe" && r.Method == http.MethodPut:
+		var req bmcapiclient.OsConfigurationIPXE
+		if !decode(w, r, &req) {
+			return
+		}
+		if s.OsConfiguration == nil {
+			s.OsConfiguration = &bmcapiclient.OsConfiguration{}
+		}
+		s.OsConfiguration.IPXE = &req
+		mockJSON(w, http.StatusOK, req)
+	case len(path) == 3 && path[1] == "actions" && r.Method == http.MethodPost:
+		m.serverAction(w, r, s, path[2])
+	case len(path) >= 2 && path[1] == "private-networks":
+		m.serveServerPrivateNetworks(w, r, s, path[2:])
+	case len(path) >= 2 && path[1] == "public-networks":
+		m.serveServerPublicNetworks(w, r, s, path[2:])
+	case len(path) >= 2 && path[1] == "ip-blocks":
+		m.serveServerIpBlocks(w, r, s, path[2:])
+	default:
+		mockNotFound(w)
+	}
+}
+
+func (m *mockAPI) createServer(w http.ResponseWriter, r *http.Request) {
+	var req bmcapiclient.ServerCreate
+	if !decode(w, r, &req) {
+		return
+	}
+	id := m.newID()
+	pricingModel := "HOURLY"
+	if req.PricingModel != nil {
+		pricingModel = *req.PricingModel
+	}
+	networkType := "PUBLIC_AND_PRIVATE"
+	if req.NetworkType != nil {
+		networkType = *req.NetworkType
+	}
+	s := &bmcapiclient.Server{
+		Id:                 id,
+		Status:             "creating",
+		Hostname:           req.Hostname,
+		Description:        req.Description,
+		Os:                 bmcapiclient.PtrString(req.Os),
+		Type:               req.Type,
+		Location:           req.Location,
+		Cpu:                "Intel Xeon E-2276G",
+		CpuCount:           1,
+		CoresPerCpu:        6,
+		CpuFrequency:       3.8,
+		Ram:                "32GB",
+		Storage:            "2x 960GB NVMe",
+		PrivateIpAddresses: []string{"10.0.0.11"},
+		ReservationId:      req.ReservationId,
+		PricingModel:       pricingModel,
+		NetworkType:        &networkType,
+		Tags:               m.serverTags(req.Tags),
+		OsConfiguration:    req.OsConfiguration,
+	}
+	if req.StorageConfiguration != nil {
+		s.StorageConfiguration = *req.StorageConfiguration
+		// the API reports the size of the root partition it created instead of -1
+		if rp := s.StorageConfiguration.RootPartition; rp != nil && rp.Size != nil && *rp.Size == -1 {
+			rp.Size = bmcapiclient.PtrInt32(893)
+		}
+	}
+	if req.NetworkConfiguration != nil {
+		s.NetworkConfiguration = *req.NetworkConfiguration
+	}
+	if err := m.assignServerNetworks(s); err != nil {
+		mockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	m.servers[id] = s
+
+	created := *s
+	created.Password = bmcapiclient.PtrString("mock-password")
+	m.transition(id, 1, func() {
+		s.Status = "powered-on"
+		provisionedOn := mockNow()
+		s.ProvisionedOn = &provisionedOn
+	})
+	mockJSON(w, http.StatusAccepted, created)
+}
+
+// assignServerNetworks makes the server a member of the networks and IP blocks requested in its network configuration.
+func (m *mockAPI) assignServerNetworks(s *bmcapiclient.Server) error {
+	nc := &s.NetworkConfiguration
+	if nc.PrivateNetworkConfiguration != nil {
+		for i, spn := range nc.PrivateNetworkConfiguration.PrivateNetworks {
+			pn, ok := m.privateNetworks[spn.Id]
+			if !ok {
+				return fmt.Errorf("private network %s not found", spn.Id)
+			}
+			ips := spn.Ips
+			if len(ips) == 0 {
+				ips = []string{fmt.Sprintf("10.0.%d.%d", pn.VlanId%256, len(pn.Memberships)+11)}
+			}
+			nc.PrivateNetworkConfiguration.PrivateNetworks[i].Ips = ips
+			nc.PrivateNetworkConfiguration.PrivateNetworks[i].VlanId = bmcapiclient.PtrInt32(pn.VlanId)
+			nc.PrivateNetworkConfiguration.PrivateNetworks[i].StatusDescription = bmcapiclient.PtrString("assigned")
+			pn.Servers = append(pn.Servers, networkapiclient.PrivateNetworkServer{Id: s.Id, Ips: ips})
+			pn.Memberships = append(pn.Memberships, networkapiclient.NetworkMembership{ResourceId: s.Id, ResourceType: "server", Ips: ips})
+		}
+	}
+	if nc.PublicNetworkConfiguration != nil {
+		for i, spn := range nc.PublicNetworkConfiguration.PublicNetworks {
+			pn, ok := m.publicNetworks[spn.Id]
+			if !ok {
+				return fmt.Errorf("public network %s not found", spn.Id)
+			}
+			nc.PublicNetworkConfiguration.PublicNetworks[i].VlanId = bmcapiclient.PtrInt32(pn.VlanId)
+			nc.PublicNetworkConfiguration.PublicNetworks[i].StatusDescription = bmcapiclient.PtrString("assigned")
+			pn.Memberships = append(pn.Memberships, networkapiclient.NetworkMembership{ResourceId: s.Id, ResourceType: "server", Ips: spn.Ips})
+			s.PublicIpAddresses = append(s.PublicIpAddresses, spn.Ips...)
+		}
+	}
+	if s.NetworkType != nil && *s.NetworkType == "PRIVATE_ONLY" {
+		return nil
+	}
+	if nc.IpBlocksConfiguration == nil {
+		nc.IpBlocksConfiguration = &bmcapiclient.IpBlocksConfiguration{ConfigurationType: bmcapiclient.PtrString("PURCHASE_NEW")}
+	}
+	ibc := nc.IpBlocksConfiguration
+	if ibc.ConfigurationType != nil && *ibc.ConfigurationType == "NONE" {
+		return nil
+	}
+	if len(ibc.IpBlocks) == 0 {
+		// a /29 is purchased on the fly, it is released along with the server
+		ib := m.newIpBlock(s.Location, "/29", nil)
+		ib.IsSystemManaged = ipapiclient.PtrBool(true)
+		ibc.IpBlocks = []bmcapiclient.ServerIpBlock{{Id: *ib.Id}}
+	}
+	for i, sib := range ibc.IpBlocks {
+		ib, ok := m.ipBlocks[sib.Id]
+		if !ok {
+			return fmt.Errorf("ip block %s not found", sib.Id)
+		}
+		m.assignIpBlock(ib, s.Id, "server")
+		if sib.VlanId == nil {
+			ibc.IpBlocks[i].VlanId = bmcapiclient.PtrInt32(10)
+		}
+		s.PublicIpAddresses = append(s.PublicIpAddresses, *ib.Cidr)
+	}
+	return nil
+}
+
+// releaseServer removes the server from the networks it is a member of and relinquishes its IP blocks.
+func (m *mockAPI) releaseServer(s *bmcapiclient.Server, deleteIpBlocks bool) {
+	for _, pn := range m.privateNetworks {
+		servers := []networkapiclient.PrivateNetworkServer{}
+		for _, v := range pn.Servers {
+			if v.Id != s.Id {
+				servers = append(servers, v)
+			}
+		}
+		pn.Servers = servers
+		pn.Memberships = withoutMember(pn.Memberships, s.Id)
+	}
+	for _, pn := range m.publicNetworks {
+		pn.Memberships = withoutMember(pn.Memberships, s.Id)
+	}
+	for id, ib := range m.ipBlocks {
+		if ib.AssignedResourceId == nil || *ib.AssignedResourceId != s.Id {
+			continue
+		}
+		if deleteIpBlocks || (ib.IsSystemManaged != nil && *ib.IsSystemManaged) {
+			delete(m.ipBlocks, id)
+			continue
+		}
+		ib.Status = ipapiclient.PtrString("unassigned")
+		ib.AssignedResourceId = nil
+		ib.AssignedResourceType = nil
+	}
+}
+
+func withoutMember(memberships []networkapiclient.NetworkMembership, resourceID string) []networkapiclient.NetworkMembership {
+	result := []networkapiclient.NetworkMembership{}
+	for _, v := range memberships {
+		if v.ResourceId != resourceID {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// serveServerPrivateNetworks adds the server to a private network or removes it, the membership is in progress until the
+// server has been polled once.
+func (m *mockAPI) serveServerPrivateNetworks(w http.ResponseWriter, r *http.Request, s *bmcapiclient.Server, path []string) {
+	nc := &s.NetworkConfiguration
+	if nc.PrivateNetworkConfiguration == nil {
+		nc.PrivateNetworkConfiguration = &bmcapiclient.PrivateNetworkConfiguration{}
+	}
+	pnc := nc.PrivateNetworkConfiguration
+	switch {
+	case len(path) == 0 && r.Method == http.MethodPost:
+		var req bmcapiclient.ServerPrivateNetwork
+		if !decode(w, r, &req) {
+			return
+		}
+		pn, ok := m.privateNetworks[req.Id]
+		if !ok {
+			mockError(w, http.StatusBadRequest, fmt.Sprintf("private network %s not found", req.Id))
+			return
+		}
+		for _, v := range pnc.PrivateNetworks {
+			if v.Id == req.Id {
+				mockError(w, http.StatusConflict, fmt.Sprintf("server is already a member of private network %s", req.Id))
+				return
+			}
+		}
+		if len(req.Ips) == 0 && (req.Dhcp == nil || !*req.Dhcp) {
+			req.Ips = []string{fmt.Sprintf("10.0.%d.%d", pn.VlanId%256, len(pn.Memberships)+11)}
+		}
+		req.VlanId = bmcapiclient.PtrInt32(pn.VlanId)
+		req.StatusDescription = bmcapiclient.PtrString("in-progress")
+		pnc.PrivateNetworks = append(pnc.PrivateNetworks, req)
+		pn.Servers = append(pn.Servers, networkapiclient.PrivateNetworkServer{Id: s.Id, Ips: req.Ips})
+		pn.Memberships = append(pn.Memberships, networkapiclient.NetworkMembership{ResourceId: s.Id, ResourceType: "server", Ips: req.Ips})
+		m.transition(s.Id+"/"+req.Id, 1, func() {
+			for i := range pnc.PrivateNetworks {
+				if pnc.PrivateNetworks[i].Id == req.Id {
+					pnc.PrivateNetworks[i].StatusDescription = bmcapiclient.PtrString("assigned")
+				}
+			}
+		})
+		mockJSON(w, http.StatusAccepted, req)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		i := -1
+		for j, v := range pnc.PrivateNetworks {
+			if v.Id == path[0] {
+				i = j
+			}
+		}
+		if i < 0 {
+			mockNotFound(w)
+			return
+		}
+		networkID := path[0]
+		pnc.PrivateNetworks[i].StatusDescription = bmcapiclient.PtrString("in-progress")
+		m.transition(s.Id+"/"+networkID, 1, func() {
+			networks := []bmcapiclient.ServerPrivateNetwork{}
+			for _, v := range pnc.PrivateNetworks {
+				if v.Id != networkID {
+					networks = append(networks, v)
+				}
+			}
+			pnc.PrivateNetworks = networks
+			if pn, ok := m.privateNetworks[networkID]; ok {
+				servers := []networkapiclient.PrivateNetworkServer{}
+				for _, v := range pn.Servers {
+					if v.Id != s.Id {
+						servers = append(servers, v)
+					}
+				}
+				pn.Servers = servers
+				pn.Memberships = withoutMember(pn.Memberships, s.Id)
+			}
+		})
+		mockText(w, http.StatusAccepted, "Server removed from private network")
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
+
+// serveServerPublicNetworks adds the server to a public network or removes it, the membership is in progress until the
+// server has been polled once.
+func (m *mockAPI) serveServerPublicNetworks(w http.ResponseWriter, r *http.Request, s *bmcapiclient.Server, path []string) {
+	nc := &s.NetworkConfiguration
+	if nc.PublicNetworkConfiguration == nil {
+		nc.PublicNetworkConfiguration = &bmcapiclient.PublicNetworkConfiguration{}
+	}
+	pnc := nc.PublicNetworkConfiguration
+	switch {
+	case len(path) == 0 && r.Method == http.MethodPost:
+		var req bmcapiclient.ServerPublicNetwork
+		if !decode(w, r, &req) {
+			return
+		}
+		pn, ok := m.publicNetworks[req.Id]
+		if !ok {
+			mockError(w, http.StatusBadRequest, fmt.Sprintf("public network %s not found", req.Id))
+			return
+		}
+		for _, v := range pnc.PublicNetworks {
+			if v.Id == req.Id {
+				mockError(w, http.StatusConflict, fmt.Sprintf("server is already a member of public network %s", req.Id))
+				return
+			}
+		}
+		req.ComputeSlaacIp = nil
+		req.VlanId = bmcapiclient.PtrInt32(pn.VlanId)
+		req.StatusDescription = bmcapiclient.PtrString("in-progress")
+		pnc.PublicNetworks = append(pnc.PublicNetworks, req)
+		pn.Memberships = append(pn.Memberships, networkapiclient.NetworkMembership{ResourceId: s.Id, ResourceType: "server", Ips: req.Ips})
+		s.PublicIpAddresses = append(s.PublicIpAddresses, req.Ips...)
+		m.transition(s.Id+"/"+req.Id, 1, func() {
+			for i := range pnc.PublicNetworks {
+				if pnc.PublicNetworks[i].Id == req.Id {
+					pnc.PublicNetworks[i].StatusDescription = bmcapiclient.PtrString("assigned")
+				}
+			}
+		})
+		mockJSON(w, http.StatusAccepted, req)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		i := -1
+		for j, v := range pnc.PublicNetworks {
+			if v.Id == path[0] {
+				i = j
+			}
+		}
+		if i < 0 {
+			mockNotFound(w)
+			return
+		}
+		networkID := path[0]
+		pnc.PublicNetworks[i].StatusDescription = bmcapiclient.PtrString("in-progress")
+		m.transition(s.Id+"/"+networkID, 1, func() {
+			networks := []bmcapiclient.ServerPublicNetwork{}
+			for _, v := range pnc.PublicNetworks {
+				if v.Id != networkID {
+					networks = append(networks, v)
+				}
+			}
+			pnc.PublicNetworks = networks
+			if pn, ok := m.publicNetworks[networkID]; ok {
+				pn.Memberships = withoutMember(pn.Memberships, s.Id)
+			}
+		})
+		mockText(w, http.StatusAccepted, "Server removed from public network")
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
+
+// serveServerIpBlocks assigns an IP block to the server or unassigns it, the status is tracked on the IP block itself.
+func (m *mockAPI) serveServerIpBlocks(w http.ResponseWriter, r *http.Request, s *bmcapiclient.Server, path []string) {
+	nc := &s.NetworkConfiguration
+	if nc.IpBlocksConfiguration == nil {
+		nc.IpBlocksConfiguration = &bmcapiclient.IpBlocksConfiguration{ConfigurationType: bmcapiclient.PtrString("USER_DEFINED")}
+	}
+	ibc := nc.IpBlocksConfiguration
+	switch {
+	case len(path) == 0 && r.Method == http.MethodPost:
+		var req bmcapiclient.ServerIpBlock
+		if !decode(w, r, &req) {
+			return
+		}
+		ib, ok := m.ipBlocks[req.Id]
+		if !ok {
+			mockError(w, http.StatusBadRequest, fmt.Sprintf("ip block %s not found", req.Id))
+			return
+		}
+		if ib.AssignedResourceId != nil {
+			mockError(w, http.StatusConflict, fmt.Sprintf("ip block %s is already assigned", req.Id))
+			return
+		}
+		if req.VlanId == nil {
+			req.VlanId = bmcapiclient.PtrInt32(10)
+		}
+		m.assignIpBlock(ib, s.Id, "server")
+		ibc.IpBlocks = append(ibc.IpBlocks, req)
+		s.PublicIpAddresses = append(s.PublicIpAddresses, *ib.Cidr)
+		mockJSON(w, http.StatusAccepted, req)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		var req bmcapiclient.RelinquishIpBlock
+		if !decode(w, r, &req) {
+			return
+		}
+		blocks := []bmcapiclient.ServerIpBlock{}
+		for _, v := range ibc.IpBlocks {
+			if v.Id != path[0] {
+				blocks = append(blocks, v)
+			}
+		}
+		ib, ok := m.ipBlocks[path[0]]
+		if !ok || len(blocks) == len(ibc.IpBlocks) {
+			mockNotFound(w)
+			return
+		}
+		ibc.IpBlocks = blocks
+		publicIps := []string{}
+		for _, v := range s.PublicIpAddresses {
+			if v != *ib.Cidr {
+				publicIps = append(publicIps, v)
+			}
+		}
+		s.PublicIpAddresses = publicIps
+		if req.DeleteIpBlocks != nil && *req.DeleteIpBlocks {
+			delete(m.ipBlocks, path[0])
+		} else {
+			m.unassignIpBlock(ib)
+		}
+		mockText(w, http.StatusAccepted, "IP block removed from server")
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
+
+func (m *mockAPI) serverAction(w http.ResponseWriter, r *http.Request, s *bmcapiclient.Server, action string) {
+	settle := func(status, final string) {
+		s.Status = status
+		m.transition(s.Id, 1, func() { s.Status = final })
+	}
+	m.serverActions[s.Id] = append(m.serverActions[s.Id], action)
+	switch action {
+	case "power-on":
+		s.Status = "powered-on"
+		mockJSON(w, http.StatusOK, bmcapiclient.ActionResult{Result: "Server powered on"})
+	case "power-off":
+		s.Status = "powered-off"
+		mockJSON(w, http.StatusOK, bmcapiclient.ActionResult{Result: "Server powered off"})
+	case "shutdown":
+		s.Status = "powered-off"
+		mockJSON(w, http.StatusOK, bmcapiclient.ActionResult{Result: "Server shutdown"})
+	case "reboot":
+		settle("rebooting", "powered-on")
+		mockJSON(w, http.StatusOK, bmcapiclient.ActionResult{Result: "Server rebooted"})
+	case "reset":
+		var req bmcapiclient.ServerReset
+		if !decode(w, r, &req) {
+			return
+		}
+		settle("resetting", "powered-on")
+		mockJSON(w, http.StatusOK, bmcapiclient.ResetResult{Result: "Server reset", Password: bmcapiclient.PtrString("mock-reset-password")})
+	case "reserve":
+		var req bmcapiclient.ServerReserve
+		if !decode(w, r, &req) {
+			return
+		}
+		s.PricingModel = req.PricingModel
+		s.ReservationId = bmcapiclient.PtrString(m.newID())
+		mockJSON(w, http.StatusOK, s)
+	case "transfer-reservation":
+		var req bmcapiclient.ReservationTransferDetails
+		if !decode(w, r, &req) {
+			return
+		}
+		target, ok := m.servers[req.TargetServerId]
+		if !ok {
+			mockNotFound(w)
+			return
+		}
+		target.ReservationId, target.PricingModel = s.ReservationId, s.PricingModel
+		s.ReservationId, s.PricingModel = nil, "HOURLY"
+		mockJSON(w, http.StatusOK, target)
+	case "deprovision":
+		var req bmcapiclient.RelinquishIpBlock
+		if !decode(w, r, &req) {
+			return
+		}
+		deleteIpBlocks := req.DeleteIpBlocks != nil && *req.DeleteIpBlocks
+		m.transition(s.Id, 1, func() {
+			m.releaseServer(s, deleteIpBlocks)
+			delete(m.servers, s.Id)
+		})
+		mockText(w, http.StatusOK, "Server Deprovisioned")
+	default:
+		mockNotFound(w)
+	}
+}
+
+// serverTags resolves tag assignment requests against the tags known to the API.
+func (m *mockAPI) serverTags(req []bmcapiclient.TagAssignmentRequest) []bmcapiclient.TagAssignment {
+	var tags []bmcapiclient.TagAssignment
+	for _, v := range req {
+		t := m.tagByName(v.Name)
+		tags = append(tags, bmcapiclient.TagAssignment{Id: t.Id, Name: t.Name, Value: v.Value, IsBillingTag: t.IsBillingTag, CreatedBy: bmcapiclient.PtrString("USER")})
+	}
+	return tags
+}
+
+func (m *mockAPI) serveSshKeys(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			keys := []bmcapiclient.SshKey{}
+			for _, k := range m.sshKeys {
+				keys = append(keys, *k)
+			}
+			mockJSON(w, http.StatusOK, keys)
+		case http.MethodPost:
+			var req bmcapiclient.SshKeyCreate
+			if !decode(w, r, &req) {
+				return
+			}
+			now := mockNow()
+			k := &bmcapiclient.SshKey{
+				Id:            m.newID(),
+				Default:       req.Default,
+				Name:          req.Name,
+				Key:           req.Key,
+				Fingerprint:   "SHA256:mock",
+				CreatedOn:     now,
+				LastUpdatedOn: now,
+			}
+			m.sshKeys[k.Id] = k
+			mockJSON(w, http.StatusCreated, k)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	k, ok := m.sshKeys[path[0]]
+	if !ok || len(path) > 1 {
+		mockNotFound(w)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockJSON(w, http.StatusOK, k)
+	case http.MethodPut:
+		var req bmcapiclient.SshKeyUpdate
+		if !decode(w, r, &req) {
+			return
+		}
+		k.Default, k.Name, k.LastUpdatedOn = req.Default, req.Name, mockNow()
+		mockJSON(w, http.StatusOK, k)
+	case http.MethodDelete:
+		delete(m.sshKeys, k.Id)
+		mockJSON(w, http.StatusOK, bmcapiclient.DeleteSshKeyResult{Result: "SSH Key deleted.", SshKeyId: k.Id})
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
+
+// productAvailability reports the stock seeded by the test for the requested product codes in every location.
+func (m *mockAPI) productAvailability(w http.ResponseWriter, r *http.Request) {
+	minQuantity := float32(1)
+	if v, err := strconv.ParseFloat(r.URL.Query().Get("minQuantity"), 32); err == nil {
+		minQuantity = float32(v)
+	}
+	availabilities := []billingapiclient.ProductAvailability{}
+	for _, code := range r.URL.Query()["productCode"] {
+		stock, ok := m.productStock[code]
+		if !ok {
+			continue
+		}
+		availability := billingapiclient.ProductAvailability{ProductCode: code, ProductCategory: "SERVER"}
+		for location, quantity := range stock {
+			availability.LocationAvailabilityDetails = append(availability.LocationAvailabilityDetails, billingapiclient.LocationAvailabilityDetail{
+				Location:             billingapiclient.ProductLocationEnum(location),
+				MinQuantityRequested: minQuantity,
+				MinQuantityAvailable: quantity >= minQuantity,
+				AvailableQuantity:    quantity,
+				Solutions:            []string{"SERVER_RANCHER"},
+			})
+		}
+		availabilities = append(availabilities, availability)
+	}
+	mockJSON(w, http.StatusOK, availabilities)
+}
+
+// serveQuotas lists the quotas seeded by the test and records quota edit requests, which stay pending.
+func (m *mockAPI) serveQuotas(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		if r.Method != http.MethodGet {
+			mockMethodNotAllowed(w)
+			return
+		}
+		quotas := []bmcapiclient.Quota{}
+		for _, q := range m.quotas {
+			quotas = append(quotas, *q)
+		}
+		mockJSON(w, http.StatusOK, quotas)
+		return
+	}
+
+	q, ok := m.quotas[path[0]]
+	if !ok {
+		mockNotFound(w)
+		return
+	}
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+		m.poll(q.Id)
+		mockJSON(w, http.StatusOK, q)
+	case len(path) == 3 && path[1] == "actions" && path[2] == "request-edit" && r.Method == http.MethodPost:
+		var req bmcapiclient.QuotaEditLimitRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		details := bmcapiclient.QuotaEditLimitRequestDetails{
+			Limit:       req.Limit,
+			Reason:      req.Reason,
+			RequestedOn: mockNow(),
+		}
+		// the request is listed on the quota after a while, unless the test drops it
+		if !m.unlistedQuotaEditRequests {
+			m.transition(q.Id, 2, func() {
+				q.QuotaEditLimitRequestDetails = append(q.QuotaEditLimitRequestDetails, details)
+			})
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		mockNotFound(w)
+	}
+}
+
+// IP API
+
+func (m *mockAPI) serveIps(w http.ResponseWriter, r *http.Request, path []string) {
+	if path[0] != "ip-blocks" {
+		mockNotFound(w)
+		return
+	}
+	path = path[1:]
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			ipBlocks := []ipapiclient.IpBlock{}
+			for _, ib := range m.ipBlocks {
+				ipBlocks = append(ipBlocks, *ib)
+			}
+			mockJSON(w, http.StatusOK, ipBlocks)
+		case http.MethodPost:
+			var req ipapiclient.IpBlockCreate
+			if !decode(w, r, &req) {
+				return
+			}
+			ib := m.newIpBlock(req.Location, req.CidrBlockSize, req.IpVersion)
+			ib.Description = req.Description
+			ib.Tags = m.ipBlockTags(req.Tags)
+			mockJSON(w, http.StatusCreated, ib)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	id := path[0]
+	if r.Method == http.MethodGet {
+		m.poll(id)
+	}
+	ib, ok := m.ipBlocks[id]
+	if !ok {
+		mockNotFound(w)
+		return
+	}
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+		mockJSON(w, http.StatusOK, ib)
+	case len(path) == 1 && r.Method == http.MethodPatch:
+		var req ipapiclient.IpBlockPatch
+		if !decode(w, r, &req) {
+			return
+		}
+		ib.Description = req.Description
+		mockJSON(w, http.StatusOK, ib)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		if *ib.Status != "unassigned" {
+			mockError(w, http.StatusConflict, "IP Block is assigned to a resource")
+			return
+		}
+		delete(m.ipBlocks, id)
+		mockJSON(w, http.StatusOK, ipapiclient.DeleteIpBlockResult{Result: ipapiclient.PtrString("IP Block deleted"), IpBlockId: ipapiclient.PtrString(id)})
+	case len(path) == 2 && path[1] == "tags" && r.Method == http.MethodPut:
+		var req []ipapiclient.TagAssignmentRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		ib.Tags = m.ipBlockTags(req)
+		mockJSON(w, http.StatusOK, ib)
+	default:
+		mockNotFound(w)
+	}
+}
+
+func (m *mockAPI) newIpBlock(location, cidrBlockSize string, ipVersion *string) *ipapiclient.IpBlock {
+	if ipVersion == nil {
+		ipVersion = ipapiclient.PtrString("V4")
+	}
+	id := m.newID()
+	createdOn := mockNow()
+	ib := &ipapiclient.IpBlock{
+		Id:              ipapiclient.PtrString(id),
+		Location:        ipapiclient.PtrString(location),
+		CidrBlockSize:   ipapiclient.PtrString(cidrBlockSize),
+		Cidr:            ipapiclient.PtrString(fmt.Sprintf("198.51.%d.%d%s", m.nextID/32%256, m.nextID%32*8, cidrBlockSize)),
+		IpVersion:       ipVersion,
+		Status:          ipapiclient.PtrString("unassigned"),
+		IsSystemManaged: ipapiclient.PtrBool(false),
+		IsBringYourOwn:  ipapiclient.PtrBool(false),
+		CreatedOn:       &createdOn,
+	}
+	m.ipBlocks[id] = ib
+	return ib
+}
+
+// assignIpBlock attaches the block to a resource, the assignment completes on the next poll.
+func (m *mockAPI) assignIpBlock(ib *ipapiclient.IpBlock, resourceID, resourceType string) {
+	ib.Status = ipapiclient.PtrString("assigning")
+	ib.AssignedResourceId = ipapiclient.PtrString(resourceID)
+	ib.AssignedResourceType = ipapiclient.PtrString(resourceType)
+	m.transition(*ib.Id, 1, func() { ib.Status = ipapiclient.PtrString("assigned") })
+}
+
+// unassignIpBlock detaches the block from its resource, the block is free again on the next poll.
+func (m *mockAPI) unassignIpBlock(ib *ipapiclient.IpBlock) {
+	ib.Status = ipapiclient.PtrString("unassigning")
+	m.transition(*ib.Id, 1, func() {
+		ib.Status = ipapiclient.PtrString("unassigned")
+		ib.AssignedResourceId = nil
+		ib.AssignedResourceType = nil
+	})
+}
+
+func (m *mockAPI) ipBlockTags(req []ipapiclient.TagAssignmentRequest) []ipapiclient.TagAssignment {
+	var tags []ipapiclient.TagAssignment
+	for _, v := range req {
+		t := m.tagByName(v.Name)
+		tags = append(tags, ipapiclient.TagAssignment{Id: t.Id, Name: t.Name, Value: v.Value, IsBillingTag: t.IsBillingTag, CreatedBy: ipapiclient.PtrString("USER")})
+	}
+	return tags
+}
+
+// Networks API
+
+func (m *mockAPI) serveNetworks(w http.ResponseWriter, r *http.Request, path []string) {
+	switch path[0] {
+	case "private-networks":
+		m.servePrivateNetworks(w, r, path[1:])
+	case "public-networks":
+		m.servePublicNetworks(w, r, path[1:])
+	case "bgp-peer-groups":
+		m.serveBgpPeerGroups(w, r, path[1:])
+	default:
+		mockNotFound(w)
+	}
+}
+
+func (m *mockAPI) nextVlanID() int32 {
+	return int32(100 + len(m.privateNetworks) + len(m.publicNetworks) + m.nextID)
+}
+
+func (m *mockAPI) servePrivateNetworks(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			networks := []networkapiclient.PrivateNetwork{}
+			for _, pn := range m.privateNetworks {
+				networks = append(networks, *pn)
+			}
+			mockJSON(w, http.StatusOK, networks)
+		case http.MethodPost:
+			var req networkapiclient.PrivateNetworkCreate
+			if !decode(w, r, &req) {
+				return
+			}
+			vlanID := m.nextVlanID()
+			if req.VlanId != nil {
+				vlanID = *req.VlanId
+			}
+			pn := &networkapiclient.PrivateNetwork{
+				Id:              m.newID(),
+				Name:            req.Name,
+				Description:     req.Description,
+				VlanId:          vlanID,
+				Type:            "PRIVATE",
+				Location:        req.Location,
+				LocationDefault: req.LocationDefault != nil && *req.LocationDefault,
+				Cidr:            req.Cidr,
+				Servers:         []networkapiclient.PrivateNetworkServer{},
+				Memberships:     []networkapiclient.NetworkMembership{},
+				Status:          "READY",
+				CreatedOn:       mockNow(),
+			}
+			m.privateNetworks[pn.Id] = pn
+			mockJSON(w, http.StatusCreated, pn)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	pn, ok := m.privateNetworks[path[0]]
+	if !ok || len(path) > 1 {
+		mockNotFound(w)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockJSON(w, http.StatusOK, pn)
+	case http.MethodPut:
+		var req networkapiclient.PrivateNetworkModify
+		if !decode(w, r, &req) {
+			return
+		}
+		pn.Name, pn.Description, pn.LocationDefault = req.Name, req.Description, req.LocationDefault
+		mockJSON(w, http.StatusOK, pn)
+	case http.MethodDelete:
+		if len(pn.Memberships) > 0 {
+			mockError(w, http.StatusConflict, "Private network is in use")
+			return
+		}
+		delete(m.privateNetworks, pn.Id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
+
+func (m *mockAPI) servePublicNetworks(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			networks := []networkapiclient.PublicNetwork{}
+			for _, pn := range m.publicNetworks {
+				networks = append(networks, *pn)
+			}
+			mockJSON(w, http.StatusOK, networks)
+		case http.MethodPost:
+			var req networkapiclient.PublicNetworkCreate
+			if !decode(w, r, &req) {
+				return
+			}
+			vlanID := m.nextVlanID()
+			if req.VlanId != nil {
+				vlanID = *req.VlanId
+			}
+			pn := &networkapiclient.PublicNetwork{
+				Id:          m.newID(),
+				VlanId:      vlanID,
+				Memberships: []networkapiclient.NetworkMembership{},
+				Name:        req.Name,
+				Location:    req.Location,
+				Description: req.Description,
+				Status:      "READY",
+				CreatedOn:   mockNow(),
+				IpBlocks:    []networkapiclient.PublicNetworkIpBlock{},
+				RaEnabled:   networkapiclient.PtrBool(req.RaEnabled != nil && *req.RaEnabled),
+			}
+			for _, v := range req.IpBlocks {
+				ib, ok := m.ipBlocks[v.Id]
+				if !ok {
+					mockError(w, http.StatusBadRequest, fmt.Sprintf("ip block %s not found", v.Id))
+					return
+				}
+				m.assignIpBlock(ib, pn.Id, "public-network")
+				pn.IpBlocks = append(pn.IpBlocks, networkapiclient.PublicNetworkIpBlock{Id: v.Id, Cidr: *ib.Cidr, UsedIpsCount: "0"})
+			}
+			m.publicNetworks[pn.Id] = pn
+			mockJSON(w, http.StatusCreated, pn)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	pn, ok := m.publicNetworks[path[0]]
+	if !ok {
+		mockNotFound(w)
+		return
+	}
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+		mockJSON(w, http.StatusOK, pn)
+	case len(path) == 1 && r.Method == http.MethodPatch:
+		var req networkapiclient.PublicNetworkModify
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != nil {
+			pn.Name = *req.Name
+		}
+		if req.Description != nil {
+			pn.Description = req.Description
+		}
+		if req.RaEnabled != nil {
+			pn.RaEnabled = req.RaEnabled
+		}
+		mockJSON(w, http.StatusOK, pn)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		if len(pn.Memberships) > 0 {
+			mockError(w, http.StatusConflict, "Public network is in use")
+			return
+		}
+		for _, v := range pn.IpBlocks {
+			if ib, ok := m.ipBlocks[v.Id]; ok {
+				m.unassignIpBlock(ib)
+			}
+		}
+		delete(m.publicNetworks, pn.Id)
+		w.WriteHeader(http.StatusNoContent)
+	case len(path) == 2 && path[1] == "ip-blocks" && r.Method == http.MethodPost:
+		var req networkapiclient.PublicNetworkIpBlockCreate
+		if !decode(w, r, &req) {
+			return
+		}
+		ib, ok := m.ipBlocks[req.Id]
+		if !ok {
+			mockError(w, http.StatusBadRequest, fmt.Sprintf("ip block %s not found", req.Id))
+			return
+		}
+		m.assignIpBlock(ib, pn.Id, "public-network")
+		pnib := networkapiclient.PublicNetworkIpBlock{Id: req.Id, Cidr: *ib.Cidr, UsedIpsCount: "0"}
+		pn.IpBlocks = append(pn.IpBlocks, pnib)
+		mockJSON(w, http.StatusCreated, pnib)
+	case len(path) == 3 && path[1] == "ip-blocks" && r.Method == http.MethodDelete:
+		ipBlocks := []networkapiclient.PublicNetworkIpBlock{}
+		for _, v := range pn.IpBlocks {
+			if v.Id != path[2] {
+				ipBlocks = append(ipBlocks, v)
+			} else if ib, ok := m.ipBlocks[v.Id]; ok {
+				m.unassignIpBlock(ib)
+			}
+		}
+		if len(ipBlocks) == len(pn.IpBlocks) {
+			mockNotFound(w)
+			return
+		}
+		pn.IpBlocks = ipBlocks
+		mockText(w, http.StatusOK, "The IP Block is being removed from the public network.")
+	default:
+		mockNotFound(w)
+	}
+}
+
+func (m *mockAPI) serveBgpPeerGroups(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			groups := []networkapiclient.BgpPeerGroup{}
+			for _, g := range m.bgpPeerGroups {
+				if location := r.URL.Query().Get("location"); location == "" || location == g.Location {
+					groups = append(groups, *g)
+				}
+			}
+			mockJSON(w, http.StatusOK, groups)
+		case http.MethodPost:
+			var req networkapiclient.BgpPeerGroupCreate
+			if !decode(w, r, &req) {
+				return
+			}
+			password := "mockPassword1"
+			if req.Password != nil {
+				password = *req.Password
+			}
+			now := mockNow().Format(time.RFC3339)
+			g := &networkapiclient.BgpPeerGroup{
+				Id:                    m.newID(),
+				Status:                "PENDING",
+				Location:              req.Location,
+				Ipv4Prefixes:          []networkapiclient.BgpIPv4Prefix{},
+				IpPrefixes:            []networkapiclient.BgpIpPrefix{},
+				TargetAsnDetails:      networkapiclient.AsnDetails{Asn: req.Asn, IsBringYourOwn: true, VerificationStatus: "PENDING"},
+				Password:              password,
+				AdvertisedRoutes:      req.AdvertisedRoutes,
+				RpkiRoaOriginAsn:      req.Asn,
+				EBgpMultiHop:          5,
+				PeeringLoopbacksV4:    []string{"169.254.247.0", "169.254.247.1"},
+				PeeringLoopbacksV6:    []string{"2001:db8::", "2001:db8::1"},
+				KeepAliveTimerSeconds: 10,
+				HoldTimerSeconds:      30,
+				CreatedOn:             networkapiclient.PtrString(now),
+				LastUpdatedOn:         networkapiclient.PtrString(now),
+			}
+			m.bgpPeerGroups[g.Id] = g
+			m.transition(g.Id, 1, func() { g.Status = "READY" })
+			mockJSON(w, http.StatusCreated, g)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	id := path[0]
+	if r.Method == http.MethodGet {
+		m.poll(id)
+	}
+	g, ok := m.bgpPeerGroups[id]
+	if !ok || len(path) > 1 {
+		mockNotFound(w)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockJSON(w, http.StatusOK, g)
+	case http.MethodPatch:
+		var req networkapiclient.BgpPeerGroupPatch
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Asn != nil {
+			g.TargetAsnDetails.Asn = *req.Asn
+			g.RpkiRoaOriginAsn = *req.Asn
+		}
+		if req.Password != nil {
+			g.Password = *req.Password
+		}
+		if req.AdvertisedRoutes != nil {
+			g.AdvertisedRoutes = *req.AdvertisedRoutes
+		}
+		g.LastUpdatedOn = networkapiclient.PtrString(mockNow().Format(time.RFC3339))
+		mockJSON(w, http.StatusOK, g)
+	case http.MethodDelete:
+		delete(m.bgpPeerGroups, id)
+		mockJSON(w, http.StatusOK, g)
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
+
+// Tag Manager API
+
+func (m *mockAPI) serveTags(w http.ResponseWriter, r *http.Request, path []string) {
+	if path[0] != "tags" {
+		mockNotFound(w)
+		return
+	}
+	path = path[1:]
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			tags := []tagapiclient.Tag{}
+			for _, t := range m.tags {
+				if name := r.URL.Query().Get("name"); name == "" || name == t.Name {
+					tags = append(tags, *t)
+				}
+			}
+			mockJSON(w, http.StatusOK, tags)
+		case http.MethodPost:
+			var req tagapiclient.TagCreate
+			if !decode(w, r, &req) {
+				return
+			}
+			for _, t := range m.tags {
+				if t.Name == req.Name {
+					mockError(w, http.StatusConflict, "Tag with name "+req.Name+" already exists")
+					return
+				}
+			}
+			t := &tagapiclient.Tag{
+				Id:           m.newID(),
+				Name:         req.Name,
+				Description:  req.Description,
+				IsBillingTag: req.IsBillingTag,
+				CreatedBy:    tagapiclient.PtrString("USER"),
+			}
+			m.tags[t.Id] = t
+			mockJSON(w, http.StatusCreated, t)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	t, ok := m.tags[path[0]]
+	if !ok || len(path) > 1 {
+		mockNotFound(w)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockJSON(w, http.StatusOK, t)
+	case http.MethodPatch:
+		var req tagapiclient.TagUpdate
+		if !decode(w, r, &req) {
+			return
+		}
+		t.Name, t.Description, t.IsBillingTag = req.Name, req.Description, req.IsBillingTag
+		mockJSON(w, http.StatusOK, t)
+	case http.MethodDelete:
+		delete(m.tags, t.Id)
+		mockJSON(w, http.StatusOK, tagapiclient.DeleteResult{Result: "Tag deleted.", TagId: t.Id})
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
+
+// tagByName returns the tag with the given name, creating it the way the API does on first assignment.
+func (m *mockAPI) tagByName(name string) *tagapiclient.Tag {
+	for _, t := range m.tags {
+		if t.Name == name {
+			return t
+		}
+	}
+	t := &tagapiclient.Tag{Id: m.newID(), Name: name, CreatedBy: tagapiclient.PtrString("USER")}
+	m.tags[t.Id] = t
+	return t
+}
+
+// Billing API
+
+func (m *mockAPI) serveBilling(w http.ResponseWriter, r *http.Request, path []string) {
+	if path[0] == "product-availability" && r.Method == http.MethodGet {
+		m.productAvailability(w, r)
+		return
+	}
+	if path[0] != "reservations" {
+		mockNotFound(w)
+		return
+	}
+	path = path[1:]
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			reservations := []billingapiclient.Reservation{}
+			for _, v := range m.reservations {
+				reservations = append(reservations, *v)
+			}
+			mockJSON(w, http.StatusOK, reservations)
+		case http.MethodPost:
+			var req billingapiclient.ReservationRequest
+			if !decode(w, r, &req) {
+				return
+			}
+			res := m.newReservation(req)
+			mockJSON(w, http.StatusCreated, res)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	res, ok := m.reservations[path[0]]
+	if !ok {
+		mockNotFound(w)
+		return
+	}
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+		mockJSON(w, http.StatusOK, res)
+	case len(path) == 3 && path[2] == "convert" && r.Method == http.MethodPost:
+		var req billingapiclient.ReservationRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		res.ReservationState = billingapiclient.RESERVATIONSTATEENUM_EXPIRED
+		mockJSON(w, http.StatusOK, m.newReservation(req))
+	case len(path) == 4 && path[2] == "auto-renew" && path[3] == "enable" && r.Method == http.MethodPost:
+		res.AutoRenew = true
+		mockJSON(w, http.StatusOK, res)
+	case len(path) == 4 && path[2] == "auto-renew" && path[3] == "disable" && r.Method == http.MethodPost:
+		var req billingapiclient.ReservationAutoRenewDisableRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		res.AutoRenew = false
+		mockJSON(w, http.StatusOK, res)
+	default:
+		mockNotFound(w)
+	}
+}
+
+func (m *mockAPI) newReservation(req billingapiclient.ReservationRequest) *billingapiclient.Reservation {
+	res := &billingapiclient.Reservation{
+		Id:               m.newID(),
+		ProductCode:      "s1.c1.small",
+		ProductCategory:  billingapiclient.RESERVATIONPRODUCTCATEGORYENUM_SERVER,
+		Location:         billingapiclient.LOCATIONENUM_PHX,
+		ReservationModel: billingapiclient.RESERVATIONMODELENUM_ONE_MONTH_RESERVATION,
+		Term:             &billingapiclient.ReservationTerm{LengthInMonths: 1, ReservationModel: billingapiclient.RESERVATIONMODELENUM_ONE_MONTH_RESERVATION},
+		ReservationState: billingapiclient.RESERVATIONSTATEENUM_ACTIVE,
+		Quantity:         req.Quantity,
+		StartDateTime:    mockNow(),
+		AutoRenew:        true,
+		Sku:              req.Sku,
+		Price:            150,
+		PriceUnit:        billingapiclient.PRICEUNITENUM_MONTH,
+	}
+	m.reservations[res.Id] = res
+	return res
+}
+
+// Network Storage API
+
+func (m *mockAPI) serveNetworkStorage(w http.ResponseWriter, r *http.Request, path []string) {
+	if path[0] != "storage-networks" {
+		mockNotFound(w)
+		return
+	}
+	path = path[1:]
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			networks := []networkstorageapiclient.StorageNetwork{}
+			for _, sn := range m.storageNetworks {
+				networks = append(networks, *sn)
+			}
+			mockJSON(w, http.StatusOK, networks)
+		case http.MethodPost:
+			var req networkstorageapiclient.StorageNetworkCreate
+			if !decode(w, r, &req) {
+				return
+			}
+			id := m.newID()
+			createdOn := mockNow()
+			sn := &networkstorageapiclient.StorageNetwork{
+				Id:          networkstorageapiclient.PtrString(id),
+				Name:        networkstorageapiclient.PtrString(req.Name),
+				Description: req.Description,
+				Status:      networkstorageapiclient.STATUS_BUSY.Ptr(),
+				Location:    networkstorageapiclient.PtrString(req.Location),
+				NetworkId:   networkstorageapiclient.PtrString(m.newID()),
+				Ips:         []string{"100.64.0.1", "100.64.0.2"},
+				CreatedOn:   &createdOn,
+			}
+			for _, v := range req.Volumes {
+				sn.Volumes = append(sn.Volumes, m.newVolume(id, v.Name, v.Description, v.PathSuffix, v.CapacityInGb, v.Tags))
+			}
+			m.storageNetworks[id] = sn
+			m.transition(id, 1, func() { sn.Status = networkstorageapiclient.STATUS_READY.Ptr() })
+			mockJSON(w, http.StatusAccepted, sn)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	id := path[0]
+	if r.Method == http.MethodGet && len(path) == 1 {
+		m.poll(id)
+	}
+	sn, ok := m.storageNetworks[id]
+	if !ok {
+		mockNotFound(w)
+		return
+	}
+	if len(path) > 1 && path[1] == "volumes" {
+		m.serveVolumes(w, r, sn, path[2:])
+		return
+	} else if len(path) > 1 {
+		mockNotFound(w)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		mockJSON(w, http.StatusOK, sn)
+	case http.MethodPatch:
+		var req networkstorageapiclient.StorageNetworkUpdate
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != nil {
+			sn.Name = req.Name
+		}
+		if req.Description != nil {
+			sn.Description = req.Description
+		}
+		mockJSON(w, http.StatusOK, sn)
+	case http.MethodDelete:
+		delete(m.storageNetworks, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
+
+func (m *mockAPI) serveVolumes(w http.ResponseWriter, r *http.Request, sn *networkstorageapiclient.StorageNetwork, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			mockJSON(w, http.StatusOK, sn.Volumes)
+		case http.MethodPost:
+			var req networkstorageapiclient.VolumeCreate
+			if !decode(w, r, &req) {
+				return
+			}
+			v := m.newVolume(*sn.Id, req.Name, req.Description, req.PathSuffix, req.CapacityInGb, req.Tags)
+			if req.Permissions != nil && req.Permissions.Nfs != nil {
+				nfs := networkstorageapiclient.NfsPermissions(*req.Permissions.Nfs)
+				v.Permissions.Nfs = &nfs
+			}
+			v.Status = networkstorageapiclient.STATUS_BUSY.Ptr()
+			sn.Volumes = append(sn.Volumes, v)
+			m.settleVolume(sn, *v.Id)
+			mockJSON(w, http.StatusAccepted, v)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	volumeID := path[0]
+	if r.Method == http.MethodGet && len(path) == 1 {
+		m.poll(volumeID)
+	}
+	i := -1
+	for j, v := range sn.Volumes {
+		if *v.Id == volumeID {
+			i = j
+		}
+	}
+	if i < 0 {
+		mockNotFound(w)
+		return
+	}
+	v := &sn.Volumes[i]
+	switch {
+	case len(path) == 1 && r.Method == http.MethodGet:
+		mockJSON(w, http.StatusOK, v)
+	case len(path) == 1 && r.Method == http.MethodPatch:
+		var req networkstorageapiclient.VolumeUpdate
+		if !decode(w, r, &req) {
+			return
+		}
+		if req.Name != nil {
+			v.Name = req.Name
+		}
+		if req.Description != nil {
+			v.Description = req.Description
+		}
+		if req.CapacityInGb != nil {
+			if *req.CapacityInGb < *v.CapacityInGb {
+				mockError(w, http.StatusBadRequest, "Volume capacity can't be decreased")
+				return
+			}
+			v.CapacityInGb = req.CapacityInGb
+		}
+		if req.PathSuffix != nil {
+			v.PathSuffix = req.PathSuffix
+			v.Path = networkstorageapiclient.PtrString("/" + *sn.Id + "/" + volumeID + *req.PathSuffix)
+		}
+		if req.Permissions != nil && req.Permissions.Nfs != nil {
+			nfs := networkstorageapiclient.NfsPermissions(*req.Permissions.Nfs)
+			v.Permissions = &networkstorageapiclient.Permissions{Nfs: &nfs}
+		}
+		v.Status = networkstorageapiclient.STATUS_BUSY.Ptr()
+		m.settleVolume(sn, volumeID)
+		mockJSON(w, http.StatusOK, v)
+	case len(path) == 1 && r.Method == http.MethodDelete:
+		v.Status = networkstorageapiclient.STATUS_DELETING.Ptr()
+		deleteRequestedOn := mockNow()
+		v.DeleteRequestedOn = &deleteRequestedOn
+		m.transition(volumeID, 1, func() {
+			volumes := []networkstorageapiclient.Volume{}
+			for _, vol := range sn.Volumes {
+				if *vol.Id != volumeID {
+					volumes = append(volumes, vol)
+				}
+			}
+			sn.Volumes = volumes
+		})
+		w.WriteHeader(http.StatusNoContent)
+	case len(path) == 2 && path[1] == "tags" && r.Method == http.MethodPut:
+		var req []networkstorageapiclient.TagAssignmentRequest
+		if !decode(w, r, &req) {
+			return
+		}
+		v.Tags = m.volumeTags(req)
+		mockJSON(w, http.StatusOK, v)
+	default:
+		mockNotFound(w)
+	}
+}
+
+// settleVolume marks the volume READY on its next poll. The volume is looked up again as the slice may have moved.
+func (m *mockAPI) settleVolume(sn *networkstorageapiclient.StorageNetwork, volumeID string) {
+	m.transition(volumeID, 1, func() {
+		for i, v := range sn.Volumes {
+			if *v.Id == volumeID {
+				sn.Volumes[i].Status = networkstorageapiclient.STATUS_READY.Ptr()
+			}
+		}
+	})
+}
+
+func (m *mockAPI) newVolume(storageNetworkID, name string, description, pathSuffix *string, capacityInGb int32, tags []networkstorageapiclient.TagAssignmentRequest) networkstorageapiclient.Volume {
+	id := m.newID()
+	createdOn := mockNow()
+	path := "/" + storageNetworkID + "/" + id
+	if pathSuffix != nil {
+		path += *pathSuffix
+	}
+	v := networkstorageapiclient.Volume{
+		Id:               networkstorageapiclient.PtrString(id),
+		Name:             networkstorageapiclient.PtrString(name),
+		Description:      description,
+		Path:             networkstorageapiclient.PtrString(path),
+		PathSuffix:       pathSuffix,
+		CapacityInGb:     networkstorageapiclient.PtrInt32(capacityInGb),
+		UsedCapacityInGb: networkstorageapiclient.PtrInt32(0),
+		Protocol:         networkstorageapiclient.PtrString("NFS"),
+		Status:           networkstorageapiclient.STATUS_READY.Ptr(),
+		CreatedOn:        &createdOn,
+		Permissions:      &networkstorageapiclient.Permissions{Nfs: &networkstorageapiclient.NfsPermissions{}},
+	}
+	v.Tags = m.volumeTags(tags)
+	return v
+}
+
+func (m *mockAPI) volumeTags(req []networkstorageapiclient.TagAssignmentRequest) []networkstorageapiclient.TagAssignment {
+	var tags []networkstorageapiclient.TagAssignment
+	for _, v := range req {
+		t := m.tagByName(v.Name)
+		tags = append(tags, networkstorageapiclient.TagAssignment{Id: t.Id, Name: t.Name, Value: v.Value, IsBillingTag: t.IsBillingTag, CreatedBy: networkstorageapiclient.PtrString("USER")})
+	}
+	return tags
+}
+
+// Rancher Solution API
+
+func (m *mockAPI) serveRancher(w http.ResponseWriter, r *http.Request, path []string) {
+	if path[0] != "clusters" {
+		mockNotFound(w)
+		return
+	}
+	path = path[1:]
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			clusters := []rancherapiclient.Cluster{}
+			for _, c := range m.clusters {
+				clusters = append(clusters, *c)
+			}
+			mockJSON(w, http.StatusOK, clusters)
+		case http.MethodPost:
+			var c rancherapiclient.Cluster
+			if !decode(w, r, &c) {
+				return
+			}
+			id := m.newID()
+			c.Id = rancherapiclient.PtrString(id)
+			if c.Name == nil {
+				c.Name = rancherapiclient.PtrString("mock-cluster-" + id[len(id)-4:])
+			}
+			if c.InitialClusterVersion == nil {
+				c.InitialClusterVersion = rancherapiclient.PtrString("v1.26.4+rke2r1")
+			}
+			if len(c.NodePools) == 0 {
+				c.NodePools = []rancherapiclient.NodePool{{
+					Name:       rancherapiclient.PtrString("pool-1"),
+					NodeCount:  rancherapiclient.PtrInt32(1),
+					ServerType: rancherapiclient.PtrString("s0.d1.small"),
+				}}
+			}
+			for i, pool := range c.NodePools {
+				count := 1
+				if pool.NodeCount != nil {
+					count = int(*pool.NodeCount)
+				}
+				for j := 0; j < count; j++ {
+					c.NodePools[i].Nodes = append(c.NodePools[i].Nodes, rancherapiclient.Node{ServerId: rancherapiclient.PtrString(m.newID())})
+				}
+			}
+			c.Metadata = &rancherapiclient.RancherServerMetadata{
+				Url:      rancherapiclient.PtrString("https://" + id + ".rancher.example.com"),
+				Username: rancherapiclient.PtrString("admin"),
+				Password: rancherapiclient.PtrString("mock-password"),
+			}
+			c.StatusDescription = rancherapiclient.PtrString("Creating")
+			cluster := &c
+			m.clusters[id] = cluster
+			m.transition(id, 1, func() { cluster.StatusDescription = rancherapiclient.PtrString("Ready") })
+			mockJSON(w, http.StatusAccepted, cluster)
+		default:
+			mockMethodNotAllowed(w)
+		}
+		return
+	}
+
+	id := path[0]
+	if r.Method == http.MethodGet {
+		m.poll(id)
+	}
+	c, ok := m.clusters[id]
+	if !ok || len(path) > 1 {
+		mockNotFound(w)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		// the rancher server credentials are only returned on creation
+		cluster := *c
+		cluster.Metadata = &rancherapiclient.RancherServerMetadata{Url: c.Metadata.Url}
+		mockJSON(w, http.StatusOK, cluster)
+	case http.MethodDelete:
+		delete(m.clusters, id)
+		mockJSON(w, http.StatusOK, rancherapiclient.DeleteResult{Result: "Cluster deleted", ClusterId: id})
+	default:
+		mockMethodNotAllowed(w)
+	}
+}
@@ -0,0 +1,356 @@
+package pnap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	billingapiclient "github.com/phoenixnap/go-sdk-bmc/billingapi/v4"
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+	ipapiclient "github.com/phoenixnap/go-sdk-bmc/ipapi/v3"
+	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
+	networkstorageapiclient "github.com/phoenixnap/go-sdk-bmc/networkstorageapi/v3"
+	rancherapiclient "github.com/phoenixnap/go-sdk-bmc/ranchersolutionapi/v3"
+	tagapiclient "github.com/phoenixnap/go-sdk-bmc/tagapi/v3"
+)
+
+const (
+	mockClientID     = "mock-client-id"
+	mockClientSecret = "mock-client-secret"
+	mockAccessToken  = "mock-access-token"
+)
+
+// mockAPI is an in-process fake of the phoenixNAP APIs used by the provider. Objects live in memory and
+// transitional statuses (e.g. creating, assigning) settle after they have been polled a few times.
+type mockAPI struct {
+	server *httptest.Server
+	URL    string
+
+	mu              sync.Mutex
+	nextID          int
+	transitions     map[string]*mockTransition
+	servers         map[string]*bmcapiclient.Server
+	sshKeys         map[string]*bmcapiclient.SshKey
+	ipBlocks        map[string]*ipapiclient.IpBlock
+	privateNetworks map[string]*networkapiclient.PrivateNetwork
+	publicNetworks  map[string]*networkapiclient.PublicNetwork
+	bgpPeerGroups   map[string]*networkapiclient.BgpPeerGroup
+	tags            map[string]*tagapiclient.Tag
+	reservations    map[string]*billingapiclient.Reservation
+	storageNetworks map[string]*networkstorageapiclient.StorageNetwork
+	clusters        map[string]*rancherapiclient.Cluster
+	quotas          map[string]*bmcapiclient.Quota
+	// productStock holds the available quantity per product code and location
+	productStock map[string]map[string]float32
+	// unlistedQuotaEditRequests drops quota edit requests instead of listing them on their quota.
+	unlistedQuotaEditRequests bool
+	// serverActions lists the actions requested per server ID, in order.
+	serverActions map[string][]string
+	// failures are returned instead of the next responses to requests of the same method, in order.
+	failures []mockFailure
+}
+
+// mockFailure is an error response injected by a test, e.g. to exercise the retries of the provider.
+type mockFailure struct {
+	method     string
+	status     int
+	retryAfter string
+}
+
+// mockTransition completes a pending status change once the object has been read polls times.
+type mockTransition struct {
+	polls    int
+	complete func()
+}
+
+// mockRoute handles the requests of a single API, path holds the segments following the API base path.
+type mockRoute func(w http.ResponseWriter, r *http.Request, path []string)
+
+// newMockAPI starts the fake API and shuts it down at the end of the test.
+func newMockAPI(t *testing.T) *mockAPI {
+	m := &mockAPI{
+		transitions:     make(map[string]*mockTransition),
+		servers:         make(map[string]*bmcapiclient.Server),
+		sshKeys:         make(map[string]*bmcapiclient.SshKey),
+		ipBlocks:        make(map[string]*ipapiclient.IpBlock),
+		privateNetworks: make(map[string]*networkapiclient.PrivateNetwork),
+		publicNetworks:  make(map[string]*networkapiclient.PublicNetwork),
+		bgpPeerGroups:   make(map[string]*networkapiclient.BgpPeerGroup),
+		tags:            make(map[string]*tagapiclient.Tag),
+		reservations:    make(map[string]*billingapiclient.Reservation),
+		storageNetworks: make(map[string]*networkstorageapiclient.StorageNetwork),
+		clusters:        make(map[string]*rancherapiclient.Cluster),
+		quotas:          make(map[string]*bmcapiclient.Quota),
+		productStock:    make(map[string]map[string]float32),
+		serverActions:   make(map[string][]string),
+	}
+	m.server = httptest.NewServer(m)
+	m.URL = m.server.URL
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockAPI) routes() map[string]mockRoute {
+	return map[string]mockRoute{
+		"/bmc/v1":                   m.serveBmc,
+		"/networks/v1":              m.serveNetworks,
+		"/ips/v1":                   m.serveIps,
+		"/tag-manager/v1":           m.serveTags,
+		"/billing/v1":               m.serveBilling,
+		"/network-storage/v1":       m.serveNetworkStorage,
+		"/solutions/rancher/v1beta": m.serveRancher,
+	}
+}
+
+func (m *mockAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if strings.HasSuffix(r.URL.Path, "/token") {
+		m.serveToken(w, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+mockAccessToken {
+		mockError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	for i, f := range m.failures {
+		if f.method != r.Method {
+			continue
+		}
+		m.failures = append(m.failures[:i], m.failures[i+1:]...)
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		mockError(w, f.status, http.StatusText(f.status))
+		return
+	}
+	for base, route := range m.routes() {
+		i := strings.Index(r.URL.Path, base+"/")
+		if i < 0 {
+			continue
+		}
+		path := strings.Split(strings.Trim(r.URL.Path[i+len(base):], "/"), "/")
+		route(w, r, path)
+		return
+	}
+	mockError(w, http.StatusNotFound, "Unknown API "+r.URL.Path)
+}
+
+// serveToken implements the client credentials grant of the authorization server.
+func (m *mockAPI) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		mockError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != mockClientID || clientSecret != mockClientSecret {
+		mockError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	mockJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": mockAccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+// newID returns a unique identifier in the format used by the API.
+func (m *mockAPI) newID() string {
+	m.nextID++
+	return fmt.Sprintf("%024x", m.nextID)
+}
+
+// transition registers a status change that completes after the object has been polled the given number of times.
+func (m *mockAPI) transition(id string, polls int, complete func()) {
+	m.transitions[id] = &mockTransition{polls: polls, complete: complete}
+}
+
+// poll advances the pending transition of the object, if any.
+func (m *mockAPI) poll(id string) {
+	t, ok := m.transitions[id]
+	if !ok {
+		return
+	}
+	t.polls--
+	if t.polls <= 0 {
+		delete(m.transitions, id)
+		t.complete()
+	}
+}
+
+// exists reports whether the object of the given Terraform resource type is still known to the API.
+func (m *mockAPI) exists(resourceType, id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ok bool
+	switch resourceType {
+	case "pnap_server":
+		_, ok = m.servers[id]
+	case "pnap_ssh_key":
+		_, ok = m.sshKeys[id]
+	case "pnap_ip_block":
+		_, ok = m.ipBlocks[id]
+	case "pnap_private_network":
+		_, ok = m.privateNetworks[id]
+	case "pnap_public_network":
+		_, ok = m.publicNetworks[id]
+	case "pnap_bgp_peer_group":
+		_, ok = m.bgpPeerGroups[id]
+	case "pnap_tag":
+		_, ok = m.tags[id]
+	case "pnap_reservation":
+		_, ok = m.reservations[id]
+	case "pnap_storage_network":
+		_, ok = m.storageNetworks[id]
+	case "pnap_storage_volume":
+		for _, sn := range m.storageNetworks {
+			for _, v := range sn.Volumes {
+				ok = ok || *v.Id == id
+			}
+		}
+	case "pnap_rancher_cluster":
+		_, ok = m.clusters[id]
+	case "pnap_server_private_network", "pnap_server_public_network", "pnap_server_ip_block":
+		ok = m.isServerMember(resourceType, id)
+	}
+	return ok
+}
+
+// isServerMember reports whether the server of a <server_id>/<member_id> ID still has the network or IP block.
+func (m *mockAPI) isServerMember(resourceType, id string) bool {
+	parts := strings.SplitN(id, "/", 2)
+	s, ok := m.servers[parts[0]]
+	if !ok || len(parts) != 2 {
+		return false
+	}
+	nc := s.NetworkConfiguration
+	switch {
+	case resourceType == "pnap_server_private_network" && nc.PrivateNetworkConfiguration != nil:
+		for _, v := range nc.PrivateNetworkConfiguration.PrivateNetworks {
+			if v.Id == parts[1] {
+				return true
+			}
+		}
+	case resourceType == "pnap_server_public_network" && nc.PublicNetworkConfiguration != nil:
+		for _, v := range nc.PublicNetworkConfiguration.PublicNetworks {
+			if v.Id == parts[1] {
+				return true
+			}
+		}
+	case resourceType == "pnap_server_ip_block" && nc.IpBlocksConfiguration != nil:
+		for _, v := range nc.IpBlocksConfiguration.IpBlocks {
+			if v.Id == parts[1] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decode reads the request body into v and answers with a bad request if it can't be parsed.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		mockError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+func mockJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func mockText(w http.ResponseWriter, code int, s string) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(code)
+	w.Write([]byte(s))
+}
+
+func mockError(w http.ResponseWriter, code int, message string) {
+	mockJSON(w, code, map[string]interface{}{"message": message})
+}
+
+func mockNotFound(w http.ResponseWriter) {
+	mockError(w, http.StatusNotFound, "Resource not found")
+}
+
+func mockMethodNotAllowed(w http.ResponseWriter) {
+	mockError(w, http.StatusMethodNotAllowed, "Method not allowed")
+}
+
+// testUnitProviderFactories returns a fresh provider for every Terraform command run by a unit test.
+func testUnitProviderFactories() map[string]func() (*schema.Provider, error) {
+	return map[string]func() (*schema.Provider, error){
+		"pnap": func() (*schema.Provider, error) {
+			return Provider(), nil
+		},
+	}
+}
+
+// testUnitProviderConfig points the provider at the fake API.
+func testUnitProviderConfig(m *mockAPI) string {
+	return testUnitProviderConfigWith(m, "")
+}
+
+// testUnitProviderConfigWith returns the provider block pointing at the fake API with additional provider settings.
+func testUnitProviderConfigWith(m *mockAPI, settings string) string {
+	return fmt.Sprintf(`
+provider "pnap" {
+	client_id = "%s"
+	client_secret = "%s"
+	token_url = "%s/auth/realms/BMC/protocol/openid-connect/token"
+	api_base_url = "%s/"
+	poll_interval = 1
+%s}
+`, mockClientID, mockClientSecret, m.URL, m.URL, settings)
+}
+
+// testUnitConfigureProvider configures the provider against the fake API with additional provider settings,
+// for tests calling resource functions directly.
+func testUnitConfigureProvider(t *testing.T, m *mockAPI, settings map[string]interface{}) *providerMeta {
+	config := map[string]interface{}{
+		"client_id":     mockClientID,
+		"client_secret": mockClientSecret,
+		"token_url":     m.URL + "/auth/realms/BMC/protocol/openid-connect/token",
+		"api_base_url":  m.URL + "/",
+		"poll_interval": 1,
+	}
+	for k, v := range settings {
+		config[k] = v
+	}
+	p := Provider()
+	if diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(config)); diags.HasError() {
+		t.Fatalf("configuring the provider: %v", diags)
+	}
+	return p.Meta().(*providerMeta)
+}
+
+// testUnitCheckDestroy verifies every resource of the given type in state has been removed from the fake API.
+func testUnitCheckDestroy(m *mockAPI, resourceType string) func(s *terraform.State) error {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if m.exists(resourceType, rs.Primary.ID) {
+				return fmt.Errorf("%s (%s) still exists", resourceType, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
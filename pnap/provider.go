@@ -1,58 +1,161 @@
 package pnap
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
 	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 )
 
+// providerMeta is passed to every resource and data source. It holds the SDK client and provider wide settings.
+type providerMeta struct {
+	client receiver.BMCSDK
+	// pollInterval overrides the backoff between status checks in waiters, if set
+	pollInterval time.Duration
+	// defaultTags are assigned to every server, IP block and storage volume on top of their own tags
+	defaultTags []defaultTag
+	// availability checks the stock and quota for new servers at plan time, if check_availability is set
+	availability *availabilityCheck
+	// retry limits the repetition of API calls failing with a throttling or transient error
+	retry retryPolicy
+	// limiter caps the concurrency and rate of API requests, nil if they aren't limited
+	limiter *requestLimiter
+	// retryAfter keeps the latest Retry-After the API sent to this configuration
+	retryAfter *retryAfterRecorder
+	// credentialSource describes where the credentials were taken from, to explain authentication failures
+	credentialSource string
+	// tokenURL routes the requests of the SDK helper to the HTTP client of this configuration, see clientRouter
+	tokenURL string
+}
+
 // Provider inits the root of provider
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"client_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("PNAP_CLIENT_ID", nil),
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 			"client_secret": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc("PNAP_CLIENT_SECRET", nil),
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
 			},
 			"config_file_path": {
 				Type:     schema.TypeString,
 				Optional: true,
 				Default:  "",
 			},
-			"token_url": {
+			"access_token": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"token_command": {
 				Type:     schema.TypeString,
 				Optional: true,
-				Default:  "",
 			},
-			"api_base_url": {
+			"profile": {
 				Type:     schema.TypeString,
 				Optional: true,
-				Default:  "",
+			},
+			"token_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PNAP_TOKEN_URL", ""),
+			},
+			"api_base_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PNAP_API_BASE_URL", ""),
+			},
+			"poll_interval": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"retry_max_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"retry_max_elapsed_time": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      300,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"check_availability": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"availability_check_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      availabilityCheckFail,
+				ValidateFunc: validation.StringInSlice([]string{availabilityCheckFail, availabilityCheckWarn}, false),
+			},
+			"default_tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"pnap_ssh_key":         resourceSshKey(),
-			"pnap_server":          resourceServer(),
-			"pnap_private_network": resourcePrivateNetwork(),
-			"pnap_reservation":     resourceReservation(),
-			"pnap_ip_block":        resourceIpBlock(),
-			"pnap_rancher_cluster": resourceRancherCluster(),
-			"pnap_tag":             resourceTag(),
-			"pnap_public_network":  resourcePublicNetwork(),
-			"pnap_storage_network": resourceStorageNetwork(),
-			"pnap_bgp_peer_group":  resourceBgpPeerGroup(),
+			"pnap_ssh_key":                resourceSshKey(),
+			"pnap_server":                 resourceServer(),
+			"pnap_private_network":        resourcePrivateNetwork(),
+			"pnap_reservation":            resourceReservation(),
+			"pnap_ip_block":               resourceIpBlock(),
+			"pnap_rancher_cluster":        resourceRancherCluster(),
+			"pnap_tag":                    resourceTag(),
+			"pnap_public_network":         resourcePublicNetwork(),
+			"pnap_storage_network":        resourceStorageNetwork(),
+			"pnap_bgp_peer_group":         resourceBgpPeerGroup(),
+			"pnap_storage_volume":         resourceStorageVolume(),
+			"pnap_server_private_network": resourceServerPrivateNetwork(),
+			"pnap_server_public_network":  resourceServerPublicNetwork(),
+			"pnap_server_ip_block":        resourceServerIpBlock(),
+			"pnap_quota_edit_request":     resourceQuotaEditRequest(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"pnap_ssh_key":              dataSourceSshKey(),
 			"pnap_server":               dataSourceServer(),
+			"pnap_servers":              dataSourceServers(),
 			"pnap_private_network":      dataSourcePrivateNetwork(),
 			"pnap_reservation":          dataSourceReservation(),
 			"pnap_ip_block":             dataSourceIpBlock(),
@@ -68,63 +171,157 @@ func Provider() *schema.Provider {
 			"pnap_invoices":             dataSourceInvoices(),
 			"pnap_transactions":         dataSourceTransactions(),
 			"pnap_bgp_peer_group":       dataSourceBgpPeerGroup(),
+			"pnap_storage_volume":       dataSourceStorageVolume(),
+			"pnap_storage_volumes":      dataSourceStorageVolumes(),
+			"pnap_ip_blocks":            dataSourceIpBlocks(),
+			"pnap_private_networks":     dataSourcePrivateNetworks(),
+			"pnap_public_networks":      dataSourcePublicNetworks(),
+			"pnap_storage_networks":     dataSourceStorageNetworks(),
+			"pnap_ssh_keys":             dataSourceSshKeys(),
+			"pnap_tags":                 dataSourceTags(),
+			"pnap_reservations":         dataSourceReservations(),
+			"pnap_bgp_peer_groups":      dataSourceBgpPeerGroups(),
+			"pnap_quotas":               dataSourceQuotas(),
 		},
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigureContext,
+	}
+}
+
+// providerConfigureContext configures the provider and releases its HTTP client once Terraform stops the provider.
+func providerConfigureContext(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	meta, err := providerConfigure(d)
+	if err != nil {
+		return nil, diag.FromErr(err)
 	}
+	// The stop context is the only notice of the provider shutting down the SDK offers.
+	if stop, ok := schema.StopContext(ctx); ok {
+		go func() {
+			<-stop.Done()
+			providerClients.unregister(meta.tokenURL)
+		}()
+	}
+	return meta, nil
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	clientId := d.Get("client_id").(string)
-	clientSecret := d.Get("client_secret").(string)
+func providerConfigure(d *schema.ResourceData) (*providerMeta, error) {
+	credentials := credentialSettings{
+		accessToken:  d.Get("access_token").(string),
+		tokenCommand: d.Get("token_command").(string),
+		clientID:     d.Get("client_id").(string),
+		clientSecret: d.Get("client_secret").(string),
+		profile:      d.Get("profile").(string),
+	}
+	// Credentials set in the provider block take precedence over the environment, so that e.g.
+	// PNAP_ACCESS_TOKEN or PNAP_CLIENT_ID and PNAP_CLIENT_SECRET don't override a profile of the provider
+	// block. A client_id of the provider block may still be completed by PNAP_CLIENT_SECRET, and the other
+	// way around.
+	if !credentials.isSet() {
+		credentials = credentialSettingsFromEnv()
+	} else if (credentials.clientID != "") || (credentials.clientSecret != "") {
+		credentials.clientID = firstNonEmpty(credentials.clientID, os.Getenv("PNAP_CLIENT_ID"))
+		credentials.clientSecret = firstNonEmpty(credentials.clientSecret, os.Getenv("PNAP_CLIENT_SECRET"))
+	}
+	clientId := credentials.clientID
+	clientSecret := credentials.clientSecret
+	profile := credentials.profile
+	accessToken := credentials.accessToken
+	tokenCommand := credentials.tokenCommand
 	configFilePath := d.Get("config_file_path").(string)
 	tokenUrl := d.Get("token_url").(string)
 	apiBaseUrl := d.Get("api_base_url").(string)
+	meta := &providerMeta{
+		pollInterval: time.Duration(d.Get("poll_interval").(int)) * time.Second,
+		defaultTags:  expandDefaultTags(d.Get("default_tags").([]interface{})),
+		retry: retryPolicy{
+			maxAttempts: d.Get("retry_max_attempts").(int),
+			maxElapsed:  time.Duration(d.Get("retry_max_elapsed_time").(int)) * time.Second,
+		},
+		limiter: newRequestLimiter(d.Get("max_concurrent_requests").(int), d.Get("requests_per_second").(float64)),
+	}
+	if d.Get("check_availability").(bool) {
+		meta.availability = newAvailabilityCheck(d.Get("availability_check_mode").(string))
+	}
 
-	configuration := dto.Configuration{}
-	configuration.UserAgent = "terraform-provider-pnap/0.33.0"
-	configuration.PoweredBy = "terraform-provider-pnap/0.33.0"
-	if (clientId != "") && (clientSecret != "") {
-		configuration.ClientID = clientId
-		configuration.ClientSecret = clientSecret
-		if (tokenUrl != "") && (apiBaseUrl != "") {
-			configuration.TokenURL = tokenUrl
-			configuration.ApiHostName = apiBaseUrl
+	// Every request of this configuration, including token requests, is sent with its own HTTP client, so
+	// that aliased providers don't share their tokens or the Retry-After of the API.
+	meta.retryAfter = newRetryAfterRecorder()
+	tokenClient := &http.Client{Transport: meta.retryAfter}
+	var token bearerToken
+	var apiHostName string
+	switch {
+	// A token minted outside of the provider takes precedence over every other credential source.
+	case (accessToken != "") || (tokenCommand != ""):
+		if accessToken != "" {
+			meta.credentialSource = "access_token"
+			external := newStaticToken(accessToken)
+			if !external.expiry.IsZero() && external.expiry.Before(time.Now()) {
+				return nil, fmt.Errorf("credential source access_token: the token expired at %s", external.expiry.Format(time.RFC3339))
+			}
+			token = external
 		} else {
-			configuration.TokenURL = "https://auth.phoenixnap.com/auth/realms/BMC/protocol/openid-connect/token"
-			configuration.ApiHostName = "https://api.phoenixnap.com/"
+			meta.credentialSource = "token_command"
+			external := newCommandToken(tokenCommand)
+			if _, err := external.get(context.Background()); err != nil {
+				return nil, fmt.Errorf("credential source token_command: %w", err)
+			}
+			token = external
 		}
-		/* auth := dto.Authentication{ClientID : clientId,
-		ClientSecret: clientSecret,
-		TokenURL: "https://auth.phoenixnap.com/auth/realms/BMC/protocol/openid-connect/token",
-		ApiHostName:"https://api.phoenixnap.com/",
-		PoweredBy: "terraform-provider-pnap"}
-		cl := newClient.NewPNAPClient(auth) */
-		cl := receiver.NewBMCSDK(configuration)
-		return cl, nil
-	}
+		apiHostName = firstNonEmpty(apiBaseUrl, defaultApiBaseURL)
 
-	if configFilePath != "" {
+	// Client credentials take precedence over the configuration file, including its profiles.
+	case (clientId != "") || (clientSecret != ""):
+		if clientId == "" {
+			return nil, fmt.Errorf("credential source client_id and client_secret: client_secret is set but client_id (or PNAP_CLIENT_ID) is missing")
+		}
+		if clientSecret == "" {
+			return nil, fmt.Errorf("credential source client_id and client_secret: client_id is set but client_secret (or PNAP_CLIENT_SECRET) is missing")
+		}
+		meta.credentialSource = "client_id and client_secret"
+		token = newClientCredentialsToken(clientId, clientSecret, firstNonEmpty(tokenUrl, defaultTokenURL), tokenClient)
+		apiHostName = firstNonEmpty(apiBaseUrl, defaultApiBaseURL)
+
+	// A profile takes precedence over the default credentials of the configuration file.
+	case profile != "":
+		path, err := resolveConfigFilePath(configFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("credential source profile %q: locating the configuration file: %w", profile, err)
+		}
+		p, err := loadProfile(path, profile)
+		if err != nil {
+			return nil, fmt.Errorf("credential source profile %q: %w", profile, err)
+		}
+		meta.credentialSource = fmt.Sprintf("profile %q in %s", profile, path)
+		token = newClientCredentialsToken(p.ClientID, p.ClientSecret, firstNonEmpty(tokenUrl, p.TokenURL, defaultTokenURL), tokenClient)
+		apiHostName = firstNonEmpty(apiBaseUrl, p.ApiBaseURL, defaultApiBaseURL)
 
-		cl, confErr := receiver.NewBMCSDKWithCustomConfig(configFilePath, configuration)
-		/* if confErr == nil {
-			auth := dto.Authentication{ClientID : "",
-			ClientSecret: "",
-			TokenURL: "",
-			ApiHostName:"",
-			PoweredBy: "terraform-provider-pnap"}
-			cl.SetAuthentication(auth)
-		} */
-		return cl, confErr
+	default:
+		path, err := resolveConfigFilePath(configFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("credential source default config file, as neither client_id and client_secret nor profile are set: %w", err)
+		}
+		meta.credentialSource = "config file " + path
+		if configFilePath == "" {
+			meta.credentialSource = "default config file " + path
+		}
+		p, err := loadDefaultCredentials(path)
+		if err != nil {
+			return nil, fmt.Errorf("credential source %s, as neither client_id and client_secret nor profile are set: %w", meta.credentialSource, err)
+		}
+		token = newClientCredentialsToken(p.ClientID, p.ClientSecret, firstNonEmpty(tokenUrl, p.TokenURL, defaultTokenURL), tokenClient)
+		apiHostName = firstNonEmpty(apiBaseUrl, p.ApiBaseURL, defaultApiBaseURL)
 	}
 
-	client, confErr := receiver.NewBMCSDKWithDefaultConfig(configuration)
-	/* if confErr == nil {
-		auth := dto.Authentication{ClientID : "",
-		ClientSecret: "",
-		TokenURL: "",
-		ApiHostName:"",
-		PoweredBy: "terraform-provider-pnap"}
-		client.SetAuthentication(auth)
-	} */
-	return client, confErr
+	// The SDK helper is handed the client through a token URL answered by providerClients, the client
+	// authenticates the requests with the token of this configuration.
+	configuration := dto.Configuration{}
+	configuration.UserAgent = "terraform-provider-pnap/0.33.0"
+	configuration.PoweredBy = "terraform-provider-pnap/0.33.0"
+	configuration.ClientID = "terraform-provider-pnap"
+	configuration.ClientSecret = "terraform-provider-pnap"
+	meta.tokenURL = providerClients.register(newProviderHTTPClient(token, meta.retryAfter))
+	configuration.TokenURL = meta.tokenURL
+	configuration.ApiHostName = apiHostName
+	log.Printf("[INFO] Using credentials from %s", meta.credentialSource)
+	meta.client = receiver.NewBMCSDK(configuration)
+	return meta, nil
 }
@@ -1,10 +1,11 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
+	"log"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/bgppeergroup"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
@@ -12,10 +13,10 @@ import (
 
 func resourceBgpPeerGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceBgpPeerGroupCreate,
-		Read:   resourceBgpPeerGroupRead,
-		Update: resourceBgpPeerGroupUpdate,
-		Delete: resourceBgpPeerGroupDelete,
+		CreateContext: resourceBgpPeerGroupCreate,
+		ReadContext:   resourceBgpPeerGroupRead,
+		UpdateContext: resourceBgpPeerGroupUpdate,
+		DeleteContext: resourceBgpPeerGroupDelete,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -182,12 +183,15 @@ func resourceBgpPeerGroup() *schema.Resource {
 				Computed: true,
 			},
 		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 	}
 }
 
-func resourceBgpPeerGroupCreate(d *schema.ResourceData, m interface{}) error {
+func resourceBgpPeerGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &networkapiclient.BgpPeerGroupCreate{}
 	request.Location = d.Get("location").(string)
@@ -204,45 +208,51 @@ func resourceBgpPeerGroupCreate(d *schema.ResourceData, m interface{}) error {
 
 	requestCommand := bgppeergroup.NewCreateBgpPeerGroupCommand(client, *request)
 
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.Id)
 
-	return resourceBgpPeerGroupRead(d, m)
+	return resourceBgpPeerGroupRead(ctx, d, m)
 }
 
-func resourceBgpPeerGroupRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceBgpPeerGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	bgpID := d.Id()
 	requestCommand := bgppeergroup.NewGetBgpPeerGroupCommand(client, bgpID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] BGP peer group (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.Id)
 	d.Set("status", resp.Status)
 	d.Set("location", resp.Location)
+	d.Set("asn", int(resp.TargetAsnDetails.Asn))
 
 	ipv4Prefixes := flattenIpv4Prefixes(resp.Ipv4Prefixes)
 	if err := d.Set("ipv4_prefixes", ipv4Prefixes); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	ipPrefixes := flattenIpPrefixes(resp.IpPrefixes)
 	if err := d.Set("ip_prefixes", ipPrefixes); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	target := resp.TargetAsnDetails
 	targetAsnDetails := flattenAsnDetails(&target)
 	if err := d.Set("target_asn_details", targetAsnDetails); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	activeAsnDetails := flattenAsnDetails(resp.ActiveAsnDetails)
 	if err := d.Set("active_asn_details", activeAsnDetails); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("password", resp.Password)
 	d.Set("advertised_routes", resp.AdvertisedRoutes)
@@ -273,9 +283,9 @@ func resourceBgpPeerGroupRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceBgpPeerGroupUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceBgpPeerGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if d.HasChange("asn") || d.HasChange("password") || d.HasChange("advertised_routes") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		request := &networkapiclient.BgpPeerGroupPatch{}
 
 		if d.HasChange("asn") {
@@ -294,27 +304,27 @@ func resourceBgpPeerGroupUpdate(d *schema.ResourceData, m interface{}) error {
 
 		requestCommand := bgppeergroup.NewUpdateBgpPeerGroupCommand(client, d.Id(), *request)
 
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 	} else {
-		return fmt.Errorf("unsupported action")
+		return diag.Errorf("unsupported action")
 	}
-	return resourceBgpPeerGroupRead(d, m)
+	return resourceBgpPeerGroupRead(ctx, d, m)
 
 }
 
-func resourceBgpPeerGroupDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceBgpPeerGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	bgpID := d.Id()
 
 	requestCommand := bgppeergroup.NewDeleteBgpPeerGroupCommand(client, bgpID)
-	_, err := requestCommand.Execute()
+	_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
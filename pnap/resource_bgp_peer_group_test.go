@@ -0,0 +1,47 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapBgpPeerGroup_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_bgp_peer_group." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_bgp_peer_group"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitBgpPeerGroupResource(rName, 65401, "DEFAULT"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "asn", "65401"),
+					resource.TestCheckResourceAttr(rLine, "advertised_routes", "DEFAULT"),
+					resource.TestCheckResourceAttrSet(rLine, "password"),
+					resource.TestCheckResourceAttrSet(rLine, "status"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testUnitBgpPeerGroupResource(rName, 65402, "NONE"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "asn", "65402"),
+					resource.TestCheckResourceAttr(rLine, "advertised_routes", "NONE"),
+					resource.TestCheckResourceAttr(rLine, "status", "READY"),
+				),
+			},
+		},
+	})
+}
+
+func testUnitBgpPeerGroupResource(rName string, asn int, advertisedRoutes string) string {
+	return fmt.Sprintf(`
+resource "pnap_bgp_peer_group" "%s" {
+	location = "PHX"
+	asn = %d
+	advertised_routes = "%s"
+}`, rName, asn, advertisedRoutes)
+}
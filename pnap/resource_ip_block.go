@@ -1,12 +1,13 @@
 package pnap
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
@@ -14,16 +15,16 @@ import (
 )
 
 const (
-	pnapIpBlockRetryDelay   = 15 * time.Second
-	pnapIpBlockRetryTimeout = 15 * time.Minute
+	pnapIpBlockRetryDelay = 15 * time.Second
 )
 
 func resourceIpBlock() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceIpBlockCreate,
-		Read:   resourceIpBlockRead,
-		Update: resourceIpBlockUpdate,
-		Delete: resourceIpBlockDelete,
+		CreateContext: resourceIpBlockCreate,
+		ReadContext:   resourceIpBlockRead,
+		UpdateContext: resourceIpBlockUpdate,
+		DeleteContext: resourceIpBlockDelete,
+		CustomizeDiff: customizeDiffTagsAll,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -88,6 +89,7 @@ func resourceIpBlock() *schema.Resource {
 					},
 				},
 			},
+			"tags_all": tagsAllSchema(),
 			"cidr": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -127,9 +129,9 @@ func resourceIpBlock() *schema.Resource {
 	}
 }
 
-func resourceIpBlockCreate(d *schema.ResourceData, m interface{}) error {
+func resourceIpBlockCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &ipapiclient.IpBlockCreate{}
 	request.Location = d.Get("location").(string)
@@ -161,29 +163,35 @@ func resourceIpBlockCreate(d *schema.ResourceData, m interface{}) error {
 		}
 		request.Tags = tagsObject
 	}
+	request.Tags = mergeDefaultTags(request.Tags, m.(*providerMeta).defaultTags)
 
 	requestCommand := ipblock.NewCreateIpBlockCommand(client, *request)
 
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if resp.Id == nil {
-		return fmt.Errorf("unknown cluster identifier")
+		return diag.Errorf("unknown cluster identifier")
 	} else {
 		d.SetId(*resp.Id)
 	}
 
-	return resourceIpBlockRead(d, m)
+	return resourceIpBlockRead(ctx, d, m)
 }
 
-func resourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceIpBlockRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	ipBlockID := d.Id()
 	requestCommand := ipblock.NewGetIpBlockCommand(client, ipBlockID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] IP block (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	if resp.Id != nil {
 		d.SetId(*resp.Id)
@@ -237,11 +245,14 @@ func resourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
 	}
 	if len(resp.Tags) > 0 {
 		var tagsInput = d.Get("tags").([]interface{})
-		tags := flattenTags(resp.Tags, tagsInput)
+		tags := flattenTags(resp.Tags, tagsInput, m.(*providerMeta).defaultTags)
 		if err := d.Set("tags", tags); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
+	if err := d.Set("tags_all", flattenTagsAll(resp.Tags)); err != nil {
+		return diag.FromErr(err)
+	}
 	if resp.IsSystemManaged != nil {
 		d.Set("is_system_managed", *resp.IsSystemManaged)
 	} else {
@@ -259,22 +270,22 @@ func resourceIpBlockRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceIpBlockUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceIpBlockUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		request := &ipapiclient.IpBlockPatch{}
 		var desc = d.Get("description").(string)
 		request.Description = &desc
 
 		ipBlockID := d.Id()
 		requestCommand := ipblock.NewPatchIpBlockCommand(client, ipBlockID, *request)
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-	} else if d.HasChange("tags") {
+	} else if d.HasChanges("tags", "tags_all") {
 		tags := d.Get("tags").([]interface{})
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		ipBlockID := d.Id()
 
 		var request []ipapiclient.TagAssignmentRequest
@@ -297,39 +308,48 @@ func resourceIpBlockUpdate(d *schema.ResourceData, m interface{}) error {
 				request[i] = tarObject
 			}
 		}
+		request = mergeDefaultTags(request, m.(*providerMeta).defaultTags)
 		requestCommand := ipblock.NewPutTagsIpBlockCommand(client, ipBlockID, request)
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else {
-		return fmt.Errorf("unsupported action")
+		return diag.Errorf("unsupported action")
 	}
 
-	return resourceIpBlockRead(d, m)
+	return resourceIpBlockRead(ctx, d, m)
 }
 
-func resourceIpBlockDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceIpBlockDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	ipBlockID := d.Id()
 
-	waitResultError := ipBlockWaitForUnassign(ipBlockID, &client)
+	waitResultError := ipBlockWaitForUnassign(ctx, ipBlockID, m.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 	if waitResultError != nil {
-		return waitResultError
+		return diag.FromErr(waitResultError)
 	}
 
 	requestCommand := ipblock.NewDeleteIpBlockCommand(client, ipBlockID)
-	_, err := requestCommand.Execute()
+	_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func flattenTags(tagsRead []ipapiclient.TagAssignment, tagsInput []interface{}) []interface{} {
+// flattenTags fills in the configured tag assignments, tags coming from the default_tags provider block are left out.
+func flattenTags(tagsRead []ipapiclient.TagAssignment, tagsInput []interface{}, defaultTags []defaultTag) []interface{} {
 	if len(tagsInput) == 0 {
+		var names []string
+		for _, l := range tagsRead {
+			names = append(names, l.Name)
+		}
+		if onlyDefaultTags(names, defaultTags) {
+			return make([]interface{}, 0)
+		}
 		tagsInput = make([]interface{}, 1)
 		tagsInputItem := make(map[string]interface{})
 		tagsInput[0] = tagsInputItem
@@ -356,19 +376,20 @@ func flattenTags(tagsRead []ipapiclient.TagAssignment, tagsInput []interface{})
 	return tagsInput
 }
 
-func ipBlockWaitForUnassign(id string, client *receiver.BMCSDK) error {
+func ipBlockWaitForUnassign(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
 	log.Printf("Waiting for ip block %s to be unassigned...", id)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"unassigning", "assigning"},
-		Target:     []string{"unassigned", "assigned"},
-		Refresh:    refreshForIpBlockStatus(client, id),
-		Timeout:    pnapIpBlockRetryTimeout,
-		Delay:      pnapIpBlockRetryDelay,
-		MinTimeout: pnapRetryMinTimeout,
+		Pending:      []string{"unassigning", "assigning"},
+		Target:       []string{"unassigned", "assigned"},
+		Refresh:      refreshForIpBlockStatus(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapIpBlockRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
 	}
 
-	_, err := stateConf.WaitForState()
+	_, err := stateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("error waiting for ip block (%s) to be unassigned: %v", id, err)
 	}
@@ -376,12 +397,12 @@ func ipBlockWaitForUnassign(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
-func refreshForIpBlockStatus(client *receiver.BMCSDK, id string) resource.StateRefreshFunc {
+func refreshForIpBlockStatus(ctx context.Context, meta *providerMeta, id string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 
-		requestCommand := ipblock.NewGetIpBlockCommand(*client, id)
+		requestCommand := ipblock.NewGetIpBlockCommand(meta.client, id)
 
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
 		if err != nil {
 			return 0, "", err
 		} else if resp.Status != nil {
@@ -2,6 +2,7 @@ package pnap
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -10,7 +11,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	helperipblock "github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	ipapiclient "github.com/phoenixnap/go-sdk-bmc/ipapi/v3"
 )
 
@@ -65,11 +65,156 @@ func TestAccPnapIpBlock_basic(t *testing.T) {
 	})
 }
 
+func TestAccPnapIpBlock_disappears(t *testing.T) {
+
+	var ipBlock ipapiclient.IpBlock
+	rNameSuffix := acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rName := "acctest-" + rNameSuffix
+	rLine := "pnap_ip_block." + rName
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckIpBlockResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				// create the ip block and delete it behind Terraform's back
+				Config: testAccCreateIpBlockResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIpBlockExists(rLine, &ipBlock),
+					testAccCheckIpBlockDisappears(rLine),
+				),
+				// the refresh drops the ip block from state, so the plan proposes recreation
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestUnitPnapIpBlock_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_ip_block." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ip_block"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testAccCreateIpBlockResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status", "unassigned"),
+					resource.TestCheckResourceAttr(rLine, "ip_version", "V6"),
+					resource.TestCheckResourceAttr(rLine, "description", "acctest"),
+					resource.TestCheckResourceAttrSet(rLine, "cidr"),
+					resource.TestCheckResourceAttrSet(rLine, "created_on"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testAccUpdateIpBlockResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "description", "acctest-basic"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPnapIpBlock_defaultTags(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_ip_block." + rName
+	defaultTags := `
+	default_tags {
+		name = "cost-center"
+		value = "42"
+	}
+	default_tags {
+		name = "env"
+		value = "dev"
+	}
+`
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ip_block"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfigWith(api, defaultTags) + testUnitIpBlockTaggedResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					// only the tag set on the resource is in state, the defaults stay hidden
+					resource.TestCheckResourceAttr(rLine, "tags.#", "1"),
+					resource.TestCheckResourceAttr(rLine, "tags.0.tag_assignment.0.name", "env"),
+					resource.TestCheckResourceAttr(rLine, "tags.0.tag_assignment.0.value", "prod"),
+					testUnitCheckIpBlockTags(api, rLine, map[string]string{"cost-center": "42", "env": "prod"}),
+					resource.TestCheckResourceAttr(rLine, "tags_all.%", "2"),
+					resource.TestCheckResourceAttr(rLine, "tags_all.cost-center", "42"),
+				),
+			},
+			{
+				// changing only the default tags updates the tags of the IP block
+				Config: testUnitProviderConfigWith(api, strings.Replace(defaultTags, `"42"`, `"43"`, 1)) + testUnitIpBlockTaggedResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "tags.#", "1"),
+					resource.TestCheckResourceAttr(rLine, "tags_all.cost-center", "43"),
+					testUnitCheckIpBlockTags(api, rLine, map[string]string{"cost-center": "43", "env": "prod"}),
+				),
+			},
+			{
+				// removing them leaves the tag set on the resource only
+				Config: testUnitProviderConfig(api) + testUnitIpBlockTaggedResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "tags_all.%", "1"),
+					testUnitCheckIpBlockTags(api, rLine, map[string]string{"env": "prod"}),
+				),
+			},
+		},
+	})
+}
+
+func testUnitIpBlockTaggedResource(rName string) string {
+	return fmt.Sprintf(`
+resource "pnap_ip_block" "%s" {
+	location = "PHX"
+	cidr_block_size = "/29"
+	tags {
+		tag_assignment {
+			name = "env"
+			value = "prod"
+		}
+	}
+}`, rName)
+}
+
+// testUnitCheckIpBlockTags verifies the tags assigned to the IP block in the fake API.
+func testUnitCheckIpBlockTags(m *mockAPI, resourceName string, expected map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		ib, ok := m.ipBlocks[rs.Primary.ID]
+		if !ok {
+			return fmt.Errorf("IP block %s not found in the API", rs.Primary.ID)
+		}
+		actual := make(map[string]string)
+		for _, t := range ib.Tags {
+			actual[t.Name] = ""
+			if t.Value != nil {
+				actual[t.Name] = *t.Value
+			}
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			return fmt.Errorf("IP block %s has tags %v, expected %v", rs.Primary.ID, actual, expected)
+		}
+		return nil
+	}
+}
+
 // testAccCheckIpBlockResourceDestroy verifies the ip block
 // has been destroyed
 func testAccCheckIpBlockResourceDestroy(s *terraform.State) error {
 	// get configured client from metadata
-	client := testAccProvider.Meta().(receiver.BMCSDK)
+	client := testAccProvider.Meta().(*providerMeta).client
 	// loop through the resources in state, verifying each ip block
 	// is destroyed
 	for _, rs := range s.RootModule().Resources {
@@ -124,7 +269,7 @@ func testAccCheckIpBlockExists(resourceName string, ipBlock *ipapiclient.IpBlock
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(receiver.BMCSDK)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		requestCommand := helperipblock.NewGetIpBlockCommand(client, rs.Primary.ID)
 
@@ -241,13 +386,30 @@ func testAccCheckIpBlockNewAttributes(resourceName string, ipBlock *ipapiclient.
 	}
 }
 
+// testAccCheckIpBlockDisappears deletes the ip block outside of Terraform
+func testAccCheckIpBlockDisappears(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+
+		requestCommand := helperipblock.NewDeleteIpBlockCommand(client, rs.Primary.ID)
+		_, err := requestCommand.Execute()
+
+		return err
+	}
+}
+
 func init() {
 	resource.AddTestSweepers("ip-block", &resource.Sweeper{
 		Name: "ip-block",
 		F: func(region string) error {
 
 			// retrieve the configured client from the test setup
-			client := testAccProvider.Meta().(receiver.BMCSDK)
+			client := testAccProvider.Meta().(*providerMeta).client
 
 			requestCommand := helperipblock.NewGetIpBlocksCommand(client)
 			resp, err := requestCommand.Execute()
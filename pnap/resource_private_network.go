@@ -1,31 +1,31 @@
 package pnap
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/privatenetwork"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
 const (
-	pnapPrivateNetworkRetryDelay   = 10 * time.Second
-	pnapPrivateNetworkRetryTimeout = 7 * time.Minute
+	pnapPrivateNetworkRetryDelay = 10 * time.Second
 )
 
 func resourcePrivateNetwork() *schema.Resource {
 	return &schema.Resource{
-		Create: resourcePrivateNetworkCreate,
-		Read:   resourcePrivateNetworkRead,
-		Update: resourcePrivateNetworkUpdate,
-		Delete: resourcePrivateNetworkDelete,
+		CreateContext: resourcePrivateNetworkCreate,
+		ReadContext:   resourcePrivateNetworkRead,
+		UpdateContext: resourcePrivateNetworkUpdate,
+		DeleteContext: resourcePrivateNetworkDelete,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -123,9 +123,9 @@ func resourcePrivateNetwork() *schema.Resource {
 	}
 }
 
-func resourcePrivateNetworkCreate(d *schema.ResourceData, m interface{}) error {
+func resourcePrivateNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &networkapiclient.PrivateNetworkCreate{}
 	request.Name = d.Get("name").(string)
@@ -154,23 +154,28 @@ func resourcePrivateNetworkCreate(d *schema.ResourceData, m interface{}) error {
 
 	requestCommand := privatenetwork.NewCreatePrivateNetworkCommandWithQuery(client, *request, query)
 
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.Id)
 
-	return resourcePrivateNetworkRead(d, m)
+	return resourcePrivateNetworkRead(ctx, d, m)
 }
 
-func resourcePrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourcePrivateNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	networkID := d.Id()
 	requestCommand := privatenetwork.NewGetPrivateNetworkCommand(client, networkID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Private network (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.Id)
@@ -184,11 +189,11 @@ func resourcePrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
 
 	servers := flattenServers(resp.Servers)
 	if err := d.Set("servers", servers); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	memberships := flattenMemberships(resp.Memberships)
 	if err := d.Set("memberships", memberships); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("status", resp.Status)
 
@@ -199,9 +204,9 @@ func resourcePrivateNetworkRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourcePrivateNetworkUpdate(d *schema.ResourceData, m interface{}) error {
+func resourcePrivateNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if d.HasChange("name") || d.HasChange("location_default") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 
 		request := &networkapiclient.PrivateNetworkModify{}
 		request.Name = d.Get("name").(string)
@@ -212,32 +217,32 @@ func resourcePrivateNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 		requestCommand := privatenetwork.NewUpdatePrivateNetworkCommand(client, d.Id(), *request)
 
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 	} else {
-		return fmt.Errorf("unsupported action")
+		return diag.Errorf("unsupported action")
 	}
-	return resourcePrivateNetworkRead(d, m)
+	return resourcePrivateNetworkRead(ctx, d, m)
 
 }
 
-func resourcePrivateNetworkDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourcePrivateNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	networkID := d.Id()
 
-	waitResultError := privateNetworkWaitForUnassign(networkID, &client)
+	waitResultError := privateNetworkWaitForUnassign(ctx, networkID, m.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 	if waitResultError != nil {
-		return waitResultError
+		return diag.FromErr(waitResultError)
 	}
 
 	requestCommand := privatenetwork.NewDeletePrivateNetworkCommand(client, networkID)
-	err := requestCommand.Execute()
+	err := executeNoContent(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -262,19 +267,20 @@ func flattenServers(servers []networkapiclient.PrivateNetworkServer) []interface
 	return make([]interface{}, 0)
 }
 
-func privateNetworkWaitForUnassign(id string, client *receiver.BMCSDK) error {
+func privateNetworkWaitForUnassign(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
 	log.Printf("Waiting for private network %s to be unassigned...", id)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"assigned"},
-		Target:     []string{"unassigned"},
-		Refresh:    refreshForPrivateNetworkMembershipStatus(client, id),
-		Timeout:    pnapPrivateNetworkRetryTimeout,
-		Delay:      pnapPrivateNetworkRetryDelay,
-		MinTimeout: pnapRetryMinTimeout,
+		Pending:      []string{"assigned"},
+		Target:       []string{"unassigned"},
+		Refresh:      refreshForPrivateNetworkMembershipStatus(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapPrivateNetworkRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
 	}
 
-	_, err := stateConf.WaitForState()
+	_, err := stateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("error waiting for private network (%s) to be unassigned: %v", id, err)
 	}
@@ -282,12 +288,12 @@ func privateNetworkWaitForUnassign(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
-func refreshForPrivateNetworkMembershipStatus(client *receiver.BMCSDK, id string) resource.StateRefreshFunc {
+func refreshForPrivateNetworkMembershipStatus(ctx context.Context, meta *providerMeta, id string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 
-		requestCommand := privatenetwork.NewGetPrivateNetworkCommand(*client, id)
+		requestCommand := privatenetwork.NewGetPrivateNetworkCommand(meta.client, id)
 
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
 		if err != nil {
 			return 0, "", err
 		} else if len(resp.Memberships) > 0 {
@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	helperprivatenetwork "github.com/PNAP/go-sdk-helper-bmc/command/networkapi/privatenetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
@@ -68,6 +67,31 @@ func TestAccPnapPrivateNetwork_basic(t *testing.T) {
 	})
 }
 
+func TestAccPnapPrivateNetwork_disappears(t *testing.T) {
+
+	var privateNetwork networkapiclient.PrivateNetwork
+	rNameSuffix := acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rName := "acctest-" + rNameSuffix
+	rLine := "pnap_private_network." + rName
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPrivateNetworkResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				// create the private network and delete it behind Terraform's back
+				Config: testAccCreatePrivateNetworkResource_basic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPrivateNetworkExists(rLine, &privateNetwork),
+					testAccCheckPrivateNetworkDisappears(rLine),
+				),
+				// the refresh drops the private network from state, so the plan proposes recreation
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
 func TestAccPnapPrivateNetwork_force(t *testing.T) {
 
 	var privateNetwork networkapiclient.PrivateNetwork
@@ -120,11 +144,39 @@ func TestAccPnapPrivateNetwork_force(t *testing.T) {
 	})
 }
 
+func TestUnitPnapPrivateNetwork_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_private_network." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_private_network"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testAccCreatePrivateNetworkResource_basic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", rName),
+					resource.TestCheckResourceAttr(rLine, "type", "PRIVATE"),
+					resource.TestCheckResourceAttr(rLine, "status", "READY"),
+					resource.TestCheckResourceAttrSet(rLine, "vlan_id"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testAccUpdatePrivateNetworkResource_basic(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", rName+"-basic"),
+					resource.TestCheckResourceAttr(rLine, "description", "acctest-basic"),
+				),
+			},
+		},
+	})
+}
+
 // testAccCheckPrivateNetworkResourceDestroy verifies the private network
 // has been destroyed
 func testAccCheckPrivateNetworkResourceDestroy(s *terraform.State) error {
 	// get configured client from metadata
-	client := testAccProvider.Meta().(receiver.BMCSDK)
+	client := testAccProvider.Meta().(*providerMeta).client
 	// loop through the resources in state, verifying each private network
 	// is destroyed
 	for _, rs := range s.RootModule().Resources {
@@ -201,7 +253,7 @@ func testAccCheckPrivateNetworkExists(resourceName string, privateNetwork *netwo
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(receiver.BMCSDK)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		requestCommand := helperprivatenetwork.NewGetPrivateNetworkCommand(client, rs.Primary.ID)
 
@@ -325,13 +377,30 @@ func testAccCheckPrivateNetworkNewAttributes_force(resourceName string, privateN
 	}
 }
 
+// testAccCheckPrivateNetworkDisappears deletes the private network outside of Terraform
+func testAccCheckPrivateNetworkDisappears(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+
+		requestCommand := helperprivatenetwork.NewDeletePrivateNetworkCommand(client, rs.Primary.ID)
+		err := requestCommand.Execute()
+
+		return err
+	}
+}
+
 func init() {
 	resource.AddTestSweepers("private-network", &resource.Sweeper{
 		Name: "private-network",
 		F: func(region string) error {
 
 			// retrieve the configured client from the test setup
-			client := testAccProvider.Meta().(receiver.BMCSDK)
+			client := testAccProvider.Meta().(*providerMeta).client
 
 			requestCommand := helperprivatenetwork.NewGetPrivateNetworksCommand(client)
 			resp, err := requestCommand.Execute()
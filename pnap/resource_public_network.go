@@ -1,31 +1,31 @@
 package pnap
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
 const (
-	pnapPublicNetworkRetryDelay   = 10 * time.Second
-	pnapPublicNetworkRetryTimeout = 7 * time.Minute
+	pnapPublicNetworkRetryDelay = 10 * time.Second
 )
 
 func resourcePublicNetwork() *schema.Resource {
 	return &schema.Resource{
-		Create: resourcePublicNetworkCreate,
-		Read:   resourcePublicNetworkRead,
-		Update: resourcePublicNetworkUpdate,
-		Delete: resourcePublicNetworkDelete,
+		CreateContext: resourcePublicNetworkCreate,
+		ReadContext:   resourcePublicNetworkRead,
+		UpdateContext: resourcePublicNetworkUpdate,
+		DeleteContext: resourcePublicNetworkDelete,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -128,9 +128,9 @@ func resourcePublicNetwork() *schema.Resource {
 	}
 }
 
-func resourcePublicNetworkCreate(d *schema.ResourceData, m interface{}) error {
+func resourcePublicNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &networkapiclient.PublicNetworkCreate{}
 	request.Name = d.Get("name").(string)
@@ -166,23 +166,28 @@ func resourcePublicNetworkCreate(d *schema.ResourceData, m interface{}) error {
 
 	requestCommand := publicnetwork.NewCreatePublicNetworkCommand(client, *request)
 
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(resp.Id)
 
-	return resourcePublicNetworkRead(d, m)
+	return resourcePublicNetworkRead(ctx, d, m)
 }
 
-func resourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourcePublicNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	networkID := d.Id()
 	requestCommand := publicnetwork.NewGetPublicNetworkCommand(client, networkID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Public network (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	d.SetId(resp.Id)
 	d.Set("name", resp.Name)
@@ -197,7 +202,7 @@ func resourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
 	ipBlocks := flattenIpBlocks(resp.IpBlocks, ipBlocksInput)
 
 	if err := d.Set("ip_blocks", ipBlocks); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if len(resp.CreatedOn.String()) > 0 {
 		d.Set("created_on", resp.CreatedOn.String())
@@ -207,7 +212,7 @@ func resourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
 	memberships := flattenMemberships(resp.Memberships)
 
 	if err := d.Set("memberships", memberships); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.Set("status", resp.Status)
 	if resp.RaEnabled != nil {
@@ -219,9 +224,9 @@ func resourcePublicNetworkRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
+func resourcePublicNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if d.HasChange("ip_blocks") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		networkID := d.Id()
 		query := &dto.Query{}
 		var force = d.Get("force").(bool)
@@ -274,13 +279,13 @@ func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 				request := &networkapiclient.PublicNetworkIpBlockCreate{}
 				request.Id = p
 				requestCommand := publicnetwork.NewAddIpBlock2PublicNetworkCommand(client, networkID, *request)
-				_, err := requestCommand.Execute()
+				_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 				if err != nil {
-					return err
+					return diag.FromErr(err)
 				}
-				waitResultError := ipBlockWaitForUnassign(p, &client)
+				waitResultError := ipBlockWaitForUnassign(ctx, p, m.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
 				if waitResultError != nil {
-					return waitResultError
+					return diag.FromErr(waitResultError)
 				}
 			}
 		}
@@ -293,18 +298,18 @@ func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 			}
 			if !idExists {
 				requestCommand := publicnetwork.NewRemoveIpBlockFromPublicNetworkCommandWithQuery(client, networkID, t, query)
-				_, err := requestCommand.Execute()
+				_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 				if err != nil {
-					return err
+					return diag.FromErr(err)
 				}
-				waitResultError := ipBlockWaitForUnassign(t, &client)
+				waitResultError := ipBlockWaitForUnassign(ctx, t, m.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
 				if waitResultError != nil {
-					return waitResultError
+					return diag.FromErr(waitResultError)
 				}
 			}
 		}
 	} else if d.HasChange("name") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		networkID := d.Id()
 		request := &networkapiclient.PublicNetworkModify{}
 		var name = d.Get("name").(string)
@@ -313,44 +318,44 @@ func resourcePublicNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 		request.Description = &desc
 
 		requestCommand := publicnetwork.NewUpdatePublicNetworkCommand(client, networkID, *request)
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else if d.HasChange("ra_enabled") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		networkID := d.Id()
 		request := &networkapiclient.PublicNetworkModify{}
 		raEnabled := d.Get("ra_enabled").(bool)
 		request.RaEnabled = &raEnabled
 
 		requestCommand := publicnetwork.NewUpdatePublicNetworkCommand(client, networkID, *request)
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else if d.HasChange("force") {
 		// Do nothing
 	} else {
-		return fmt.Errorf("unsupported action")
+		return diag.Errorf("unsupported action")
 	}
-	return resourcePublicNetworkRead(d, m)
+	return resourcePublicNetworkRead(ctx, d, m)
 }
 
-func resourcePublicNetworkDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourcePublicNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	networkID := d.Id()
 
-	waitResultError := publicNetworkWaitForUnassign(networkID, &client)
+	waitResultError := publicNetworkWaitForUnassign(ctx, networkID, m.(*providerMeta), d.Timeout(schema.TimeoutDelete))
 	if waitResultError != nil {
-		return waitResultError
+		return diag.FromErr(waitResultError)
 	}
 
 	requestCommand := publicnetwork.NewDeletePublicNetworkCommand(client, networkID)
-	err := requestCommand.Execute()
+	err := executeNoContent(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
@@ -375,19 +380,20 @@ func flattenMemberships(memberships []networkapiclient.NetworkMembership) []inte
 	return make([]interface{}, 0)
 }
 
-func publicNetworkWaitForUnassign(id string, client *receiver.BMCSDK) error {
+func publicNetworkWaitForUnassign(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
 	log.Printf("Waiting for public network %s to be unassigned...", id)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"assigned"},
-		Target:     []string{"unassigned"},
-		Refresh:    refreshForPublicNetworkMembershipStatus(client, id),
-		Timeout:    pnapPublicNetworkRetryTimeout,
-		Delay:      pnapPublicNetworkRetryDelay,
-		MinTimeout: pnapRetryMinTimeout,
+		Pending:      []string{"assigned"},
+		Target:       []string{"unassigned"},
+		Refresh:      refreshForPublicNetworkMembershipStatus(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapPublicNetworkRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
 	}
 
-	_, err := stateConf.WaitForState()
+	_, err := stateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("error waiting for public network (%s) to be unassigned: %v", id, err)
 	}
@@ -395,12 +401,12 @@ func publicNetworkWaitForUnassign(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
-func refreshForPublicNetworkMembershipStatus(client *receiver.BMCSDK, id string) resource.StateRefreshFunc {
+func refreshForPublicNetworkMembershipStatus(ctx context.Context, meta *providerMeta, id string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 
-		requestCommand := publicnetwork.NewGetPublicNetworkCommand(*client, id)
+		requestCommand := publicnetwork.NewGetPublicNetworkCommand(meta.client, id)
 
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
 		if err != nil {
 			return 0, "", err
 		} else if len(resp.Memberships) > 0 {
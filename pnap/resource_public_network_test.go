@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	helperpublicnetwork "github.com/PNAP/go-sdk-helper-bmc/command/networkapi/publicnetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	networkapiclient "github.com/phoenixnap/go-sdk-bmc/networkapi/v4"
 )
 
@@ -64,11 +63,91 @@ func TestAccPnapPublicNetwork_basic(t *testing.T) {
 	})
 }
 
+func TestAccPnapPublicNetwork_disappears(t *testing.T) {
+
+	var publicNetwork networkapiclient.PublicNetwork
+	rNameSuffix := acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rName := "acctest-" + rNameSuffix
+	rLine := "pnap_public_network." + rName
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPublicNetworkResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				// create the public network and delete it behind Terraform's back
+				Config: testAccCreatePublicNetworkResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPublicNetworkExists(rLine, &publicNetwork),
+					testAccCheckPublicNetworkDisappears(rLine),
+				),
+				// the refresh drops the public network from state, so the plan proposes recreation
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestUnitPnapPublicNetwork_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_public_network." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_public_network"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testAccCreatePublicNetworkResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", rName),
+					resource.TestCheckResourceAttr(rLine, "status", "READY"),
+					resource.TestCheckResourceAttrSet(rLine, "vlan_id"),
+					resource.TestCheckResourceAttrSet(rLine, "created_on"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testAccUpdatePublicNetworkResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", rName+"-basic"),
+					resource.TestCheckResourceAttr(rLine, "description", "acctest-basic"),
+				),
+			},
+			{
+				// the ip block goes through assigning before the update completes
+				Config: testUnitProviderConfig(api) + testUnitPublicNetworkWithIpBlockResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "ip_blocks.#", "1"),
+					resource.TestCheckResourceAttrSet(rLine, "ip_blocks.0.public_network_ip_block.0.cidr"),
+				),
+			},
+		},
+	})
+}
+
+func testUnitPublicNetworkWithIpBlockResource(rName string) string {
+	return fmt.Sprintf(`
+resource "pnap_ip_block" "%s" {
+	location = "PHX"
+	cidr_block_size = "/29"
+}
+
+resource "pnap_public_network" "%s" {
+	name = "%s-basic"
+	location = "PHX"
+	description = "acctest-basic"
+	ip_blocks {
+		public_network_ip_block {
+			id = pnap_ip_block.%s.id
+		}
+	}
+}`, rName, rName, rName, rName)
+}
+
 // testAccCheckPublicNetworkResourceDestroy verifies the public network
 // has been destroyed
 func testAccCheckPublicNetworkResourceDestroy(s *terraform.State) error {
 	// get configured client from metadata
-	client := testAccProvider.Meta().(receiver.BMCSDK)
+	client := testAccProvider.Meta().(*providerMeta).client
 	// loop through the resources in state, verifying each public network
 	// is destroyed
 	for _, rs := range s.RootModule().Resources {
@@ -121,7 +200,7 @@ func testAccCheckPublicNetworkExists(resourceName string, publicNetwork *network
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(receiver.BMCSDK)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		requestCommand := helperpublicnetwork.NewGetPublicNetworkCommand(client, rs.Primary.ID)
 
@@ -181,13 +260,30 @@ func testAccCheckPublicNetworkNewAttributes(resourceName string, publicNetwork *
 	}
 }
 
+// testAccCheckPublicNetworkDisappears deletes the public network outside of Terraform
+func testAccCheckPublicNetworkDisappears(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+
+		requestCommand := helperpublicnetwork.NewDeletePublicNetworkCommand(client, rs.Primary.ID)
+		err := requestCommand.Execute()
+
+		return err
+	}
+}
+
 func init() {
 	resource.AddTestSweepers("public-network", &resource.Sweeper{
 		Name: "public-network",
 		F: func(region string) error {
 
 			// retrieve the configured client from the test setup
-			client := testAccProvider.Meta().(receiver.BMCSDK)
+			client := testAccProvider.Meta().(*providerMeta).client
 
 			requestCommand := helperpublicnetwork.NewGetPublicNetworksCommand(client)
 			resp, err := requestCommand.Execute()
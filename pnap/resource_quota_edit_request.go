@@ -0,0 +1,236 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/quota"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+// Statuses of a quota edit request. The API only lists pending requests, so the outcome is derived
+// from the limit of the quota once the request is gone.
+const (
+	quotaEditRequestPending = "PENDING"
+	quotaEditRequestApplied = "APPLIED"
+	quotaEditRequestClosed  = "CLOSED"
+)
+
+// quotaEditRequestLookups is the number of times a submitted edit request is looked up on its quota before giving up.
+const quotaEditRequestLookups = 5
+
+func resourceQuotaEditRequest() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceQuotaEditRequestCreate,
+		ReadContext:   resourceQuotaEditRequestRead,
+		DeleteContext: resourceQuotaEditRequestDelete,
+
+		Schema: map[string]*schema.Schema{
+			"quota_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"reason": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"request_status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"requested_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"current_limit": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"unit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"used": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"quota_edit_limit_request_details": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"limit": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"requested_on": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceQuotaEditRequestCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	quotaID := d.Get("quota_id").(string)
+	request := &bmcapiclient.QuotaEditLimitRequest{}
+	request.Limit = int32(d.Get("limit").(int))
+	request.Reason = d.Get("reason").(string)
+
+	requestCommand := quota.NewRequestEditQuotaCommand(client, quotaID, *request)
+	err := executeNoContent(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The API doesn't return an identifier for the request, it is identified by the time it was made.
+	stateConf := &resource.StateChangeConf{
+		Pending:        []string{},
+		Target:         []string{quotaEditRequestPending},
+		Refresh:        quotaEditRequestRefreshForCreate(ctx, m.(*providerMeta), quotaID, *request),
+		Timeout:        d.Timeout(schema.TimeoutCreate),
+		MinTimeout:     pnapRetryMinTimeout,
+		PollInterval:   m.(*providerMeta).pollInterval,
+		NotFoundChecks: quotaEditRequestLookups,
+	}
+	requestedOn, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return diag.Errorf("error looking up the edit request of quota (%s), it was submitted but isn't listed on the quota: %v", quotaID, err)
+	}
+	d.SetId(quotaEditRequestID(quotaID, requestedOn.(time.Time)))
+	return resourceQuotaEditRequestRead(ctx, d, m)
+}
+
+// quotaEditRequestRefreshForCreate looks up the time of the latest listed edit request matching request, the result is
+// nil while the API doesn't list the request yet.
+func quotaEditRequestRefreshForCreate(ctx context.Context, meta *providerMeta, quotaID string, request bmcapiclient.QuotaEditLimitRequest) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		requestCommand := quota.NewGetQuotaCommand(meta.client, quotaID)
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
+		if err != nil {
+			return nil, "", err
+		}
+		var requestedOn *time.Time
+		for _, details := range resp.QuotaEditLimitRequestDetails {
+			if details.Limit == request.Limit && details.Reason == request.Reason && (requestedOn == nil || details.RequestedOn.After(*requestedOn)) {
+				requestedOn = &details.RequestedOn
+			}
+		}
+		if requestedOn == nil {
+			return nil, "", nil
+		}
+		return *requestedOn, quotaEditRequestPending, nil
+	}
+}
+
+// quotaEditRequestID returns the ID of an edit request in the <quota_id>/<requested_on> format, as several requests
+// may be made for the same quota.
+func quotaEditRequestID(quotaID string, requestedOn time.Time) string {
+	return quotaID + "/" + requestedOn.UTC().Format(time.RFC3339Nano)
+}
+
+// parseQuotaEditRequestID splits the ID of an edit request. IDs made of the quota ID only are accepted as well,
+// the time of the request is zero then.
+func parseQuotaEditRequestID(id string) (string, time.Time, error) {
+	quotaID, requested, ok := strings.Cut(id, "/")
+	if !ok {
+		return id, time.Time{}, nil
+	}
+	requestedOn, err := time.Parse(time.RFC3339Nano, requested)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("unexpected format of ID (%s), expected <quota_id>/<requested_on>: %w", id, err)
+	}
+	return quotaID, requestedOn, nil
+}
+
+func resourceQuotaEditRequestRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	quotaID, requestedOn, err := parseQuotaEditRequestID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	requestCommand := quota.NewGetQuotaCommand(client, quotaID)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Quota (%s) not found, removing from state", quotaID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	for k, v := range flattenDataQuota(*resp) {
+		switch k {
+		case "id", "description":
+			continue
+		case "limit":
+			d.Set("current_limit", v)
+		default:
+			d.Set(k, v)
+		}
+	}
+
+	limit := int32(d.Get("limit").(int))
+	reason := d.Get("reason").(string)
+	status := quotaEditRequestClosed
+	for _, details := range resp.QuotaEditLimitRequestDetails {
+		if details.Limit == limit && details.Reason == reason && (requestedOn.IsZero() || details.RequestedOn.Equal(requestedOn)) {
+			status = quotaEditRequestPending
+			d.Set("requested_on", details.RequestedOn.String())
+		}
+	}
+	if status != quotaEditRequestPending && resp.Limit == limit {
+		status = quotaEditRequestApplied
+	}
+	d.Set("request_status", status)
+
+	return nil
+}
+
+func resourceQuotaEditRequestDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if d.Get("request_status").(string) == quotaEditRequestPending {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "Quota edit request is still pending",
+			Detail:        "The API doesn't support withdrawing a quota edit request, the request for quota " + d.Get("quota_id").(string) + " has only been removed from the state.",
+			AttributePath: cty.GetAttrPath("request_status"),
+		})
+	}
+	return diags
+}
@@ -0,0 +1,101 @@
+package pnap
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func TestUnitPnapQuotaEditRequest_basic(t *testing.T) {
+	api := newMockAPI(t)
+	api.quotas["bmc.servers.max_count"] = &bmcapiclient.Quota{
+		Id:     "bmc.servers.max_count",
+		Name:   "Servers",
+		Status: "OK",
+		Limit:  10,
+		Unit:   "COUNT",
+		Used:   8,
+	}
+	rLine := "pnap_quota_edit_request.servers"
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitQuotaEditRequestResource("bmc.servers.max_count", 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "request_status", "PENDING"),
+					resource.TestCheckResourceAttr(rLine, "current_limit", "10"),
+					resource.TestCheckResourceAttr(rLine, "quota_edit_limit_request_details.#", "1"),
+					resource.TestCheckResourceAttr(rLine, "quota_edit_limit_request_details.0.limit", "20"),
+					resource.TestCheckResourceAttrSet(rLine, "requested_on"),
+					resource.TestMatchResourceAttr(rLine, "id", regexp.MustCompile(`^bmc\.servers\.max_count/\d{4}-\d{2}-\d{2}T`)),
+				),
+			},
+			{
+				// a second request for the same quota is tracked on its own
+				Config: testUnitProviderConfig(api) + testUnitQuotaEditRequestResource("bmc.servers.max_count", 20) + `
+resource "pnap_quota_edit_request" "more_servers" {
+	quota_id = "bmc.servers.max_count"
+	limit = 30
+	reason = "Headroom for a second cluster"
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "request_status", "PENDING"),
+					resource.TestCheckResourceAttr("pnap_quota_edit_request.more_servers", "request_status", "PENDING"),
+					resource.TestCheckResourceAttr("pnap_quota_edit_request.more_servers", "quota_edit_limit_request_details.#", "2"),
+				),
+			},
+			{
+				// the request is approved and the new limit applied
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					q := api.quotas["bmc.servers.max_count"]
+					q.Limit, q.QuotaEditLimitRequestDetails = 20, nil
+				},
+				Config: testUnitProviderConfig(api) + testUnitQuotaEditRequestResource("bmc.servers.max_count", 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "request_status", "APPLIED"),
+					resource.TestCheckResourceAttr(rLine, "current_limit", "20"),
+					resource.TestCheckResourceAttr(rLine, "quota_edit_limit_request_details.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPnapQuotaEditRequest_unlisted(t *testing.T) {
+	api := newMockAPI(t)
+	api.quotas["bmc.servers.max_count"] = &bmcapiclient.Quota{
+		Id:     "bmc.servers.max_count",
+		Name:   "Servers",
+		Status: "OK",
+		Limit:  10,
+		Unit:   "COUNT",
+		Used:   8,
+	}
+	api.unlistedQuotaEditRequests = true
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				// the request can't be told apart from others without the time it was made
+				Config:      testUnitProviderConfig(api) + testUnitQuotaEditRequestResource("bmc.servers.max_count", 20),
+				ExpectError: regexp.MustCompile(`error looking up the edit request of quota \(bmc\.servers\.max_count\)`),
+			},
+		},
+	})
+}
+
+func testUnitQuotaEditRequestResource(quotaID string, limit int) string {
+	return fmt.Sprintf(`
+resource "pnap_quota_edit_request" "servers" {
+	quota_id = "%s"
+	limit = %d
+	reason = "Headroom for a new cluster"
+}`, quotaID, limit)
+}
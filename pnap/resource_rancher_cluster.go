@@ -1,11 +1,14 @@
 package pnap
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/ranchersolutionapi/cluster"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	rancherapiclient "github.com/phoenixnap/go-sdk-bmc/ranchersolutionapi/v3"
@@ -13,14 +16,12 @@ import (
 
 func resourceRancherCluster() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceRancherClusterCreate,
-		Read:   resourceRancherClusterRead,
-		Update: resourceRancherClusterUpdate,
-		Delete: resourceRancherClusterDelete,
+		CreateContext: resourceRancherClusterCreate,
+		ReadContext:   resourceRancherClusterRead,
+		DeleteContext: resourceRancherClusterDelete,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
-			Update: schema.DefaultTimeout(pnapRetryTimeout),
 			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
 		},
 
@@ -28,15 +29,18 @@ func resourceRancherCluster() *schema.Resource {
 			"name": {
 				Type:     schema.TypeString,
 				Optional: true,
+				ForceNew: true,
 				Computed: true,
 			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
+				ForceNew: true,
 			},
 			"location": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"initial_cluster_version": {
 				Type:     schema.TypeString,
@@ -45,6 +49,7 @@ func resourceRancherCluster() *schema.Resource {
 			"node_pools": {
 				Type:     schema.TypeList,
 				Optional: true,
+				ForceNew: true,
 				Computed: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
@@ -52,39 +57,50 @@ func resourceRancherCluster() *schema.Resource {
 						"name": {
 							Type:     schema.TypeString,
 							Optional: true,
+							ForceNew: true,
 							Computed: true,
 						},
 						"node_count": {
 							Type:     schema.TypeInt,
 							Optional: true,
+							ForceNew: true,
 							Computed: true,
 						},
 						"server_type": {
 							Type:     schema.TypeString,
 							Optional: true,
+							ForceNew: true,
 							Computed: true,
 						},
 						"ssh_config": {
-							Type:     schema.TypeList,
-							Optional: true,
-							MaxItems: 1,
+							Type:             schema.TypeList,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
+							MaxItems:         1,
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"install_default_keys": {
-										Type:     schema.TypeBool,
-										Optional: true,
+										Type:             schema.TypeBool,
+										Optional:         true,
+										ForceNew:         true,
+										DiffSuppressFunc: suppressUnreadClusterArgument,
 									},
 									"keys": {
-										Type:     schema.TypeSet,
-										Optional: true,
-										Computed: true,
-										Elem:     &schema.Schema{Type: schema.TypeString},
+										Type:             schema.TypeSet,
+										Optional:         true,
+										ForceNew:         true,
+										DiffSuppressFunc: suppressUnreadClusterArgument,
+										Computed:         true,
+										Elem:             &schema.Schema{Type: schema.TypeString},
 									},
 									"key_ids": {
-										Type:     schema.TypeSet,
-										Optional: true,
-										Computed: true,
-										Elem:     &schema.Schema{Type: schema.TypeString},
+										Type:             schema.TypeSet,
+										Optional:         true,
+										ForceNew:         true,
+										DiffSuppressFunc: suppressUnreadClusterArgument,
+										Computed:         true,
+										Elem:             &schema.Schema{Type: schema.TypeString},
 									},
 								},
 							},
@@ -105,54 +121,75 @@ func resourceRancherCluster() *schema.Resource {
 				},
 			},
 			"configuration": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
+				Type:             schema.TypeList,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressUnreadClusterArgument,
+				MaxItems:         1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"token": {
 							Type:      schema.TypeString,
 							Optional:  true,
+							ForceNew:  true,
 							Sensitive: true,
 						},
 						"tls_san": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
 						},
 						"etcd_snapshot_schedule_cron": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
 						},
 						"etcd_snapshot_retention": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  5,
+							Type:             schema.TypeInt,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
+							Default:          5,
 						},
 						"node_taint": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
 						},
 						"cluster_domain": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
 						},
 						"certificates": {
-							Type:     schema.TypeList,
-							Optional: true,
-							MaxItems: 1,
+							Type:             schema.TypeList,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
+							MaxItems:         1,
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"ca_certificate": {
-										Type:     schema.TypeString,
-										Optional: true,
+										Type:             schema.TypeString,
+										Optional:         true,
+										ForceNew:         true,
+										DiffSuppressFunc: suppressUnreadClusterArgument,
 									},
 									"certificate": {
-										Type:     schema.TypeString,
-										Optional: true,
+										Type:             schema.TypeString,
+										Optional:         true,
+										ForceNew:         true,
+										DiffSuppressFunc: suppressUnreadClusterArgument,
 									},
 									"certificate_key": {
-										Type:     schema.TypeString,
-										Optional: true,
+										Type:             schema.TypeString,
+										Optional:         true,
+										ForceNew:         true,
+										DiffSuppressFunc: suppressUnreadClusterArgument,
 									},
 								},
 							},
@@ -183,27 +220,37 @@ func resourceRancherCluster() *schema.Resource {
 				},
 			},
 			"workload_configuration": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
+				Type:             schema.TypeList,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressUnreadClusterArgument,
+				MaxItems:         1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
-							Type:     schema.TypeString,
-							Optional: true,
+							Type:             schema.TypeString,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
 						},
 						"server_count": {
-							Type:     schema.TypeInt,
-							Optional: true,
-							Default:  1,
+							Type:             schema.TypeInt,
+							Optional:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
+							Default:          1,
 						},
 						"server_type": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
 						},
 						"location": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:             schema.TypeString,
+							Required:         true,
+							ForceNew:         true,
+							DiffSuppressFunc: suppressUnreadClusterArgument,
 						},
 					},
 				},
@@ -213,12 +260,15 @@ func resourceRancherCluster() *schema.Resource {
 				Computed: true,
 			},
 		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 	}
 }
 
-func resourceRancherClusterCreate(d *schema.ResourceData, m interface{}) error {
+func resourceRancherClusterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &rancherapiclient.Cluster{}
 	var name = d.Get("name").(string)
@@ -359,11 +409,11 @@ func resourceRancherClusterCreate(d *schema.ResourceData, m interface{}) error {
 	}
 
 	requestCommand := cluster.NewCreateClusterCommand(client, *request)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	} else if resp.Id == nil {
-		return fmt.Errorf("unknown cluster identifier")
+		return diag.Errorf("unknown cluster identifier")
 	} else {
 		d.SetId(*resp.Id)
 		if resp.Metadata != nil {
@@ -382,26 +432,31 @@ func resourceRancherClusterCreate(d *schema.ResourceData, m interface{}) error {
 			d.Set("metadata", metadata)
 		}
 
-		waitResultError := clusterWaitForCreate(*resp.Id, &client)
+		waitResultError := clusterWaitForCreate(ctx, *resp.Id, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 		if waitResultError != nil {
-			return waitResultError
+			return diag.FromErr(waitResultError)
 		}
 	}
 
-	return resourceRancherClusterRead(d, m)
+	return resourceRancherClusterRead(ctx, d, m)
 }
 
-func resourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceRancherClusterRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	clusterID := d.Id()
 
 	requestCommand := cluster.NewGetClusterCommand(client, clusterID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Rancher cluster (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	if resp.Id == nil {
-		return fmt.Errorf("unknown cluster identifier")
+		return diag.Errorf("unknown cluster identifier")
 	}
 	d.SetId(*resp.Id)
 	if resp.Name != nil {
@@ -418,7 +473,7 @@ func resourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 		var np = d.Get("node_pools").([]interface{})
 		flatPools := flattenNodePools(resp.NodePools, np)
 		if err := d.Set("node_pools", flatPools); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 	if resp.StatusDescription != nil {
@@ -427,22 +482,14 @@ func resourceRancherClusterRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceRancherClusterUpdate(d *schema.ResourceData, m interface{}) error {
-	if d.HasChange("location") || d.HasChange("name") || d.HasChange("description") || d.HasChange("node_pools") || d.HasChange("configuration") ||
-		d.HasChange("workload_configuration") {
-		return fmt.Errorf("unsupported action")
-	}
-	return resourceRancherClusterRead(d, m)
-}
-
-func resourceRancherClusterDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceRancherClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	clusterID := d.Id()
 
 	requestCommand := cluster.NewDeleteClusterCommand(client, clusterID)
-	_, err := requestCommand.Execute()
+	_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	return nil
 }
@@ -482,19 +529,20 @@ func flattenNodePools(nodePools []rancherapiclient.NodePool, np []interface{}) [
 	return np
 }
 
-func clusterWaitForCreate(id string, client *receiver.BMCSDK) error {
+func clusterWaitForCreate(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
 	log.Printf("Waiting for cluster %s to be created...", id)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"Creating"},
-		Target:     []string{"Ready", "Error"},
-		Refresh:    clusterRefreshForCreate(client, id),
-		Timeout:    pnapRetryTimeout,
-		Delay:      pnapRetryDelay,
-		MinTimeout: pnapRetryMinTimeout,
+		Pending:      []string{"Creating"},
+		Target:       []string{"Ready", "Error"},
+		Refresh:      clusterRefreshForCreate(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
 	}
 
-	_, err := stateConf.WaitForState()
+	_, err := stateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("error waiting for cluster (%s) to switch to target state: %v", id, err)
 	}
@@ -502,12 +550,12 @@ func clusterWaitForCreate(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
-func clusterRefreshForCreate(client *receiver.BMCSDK, id string) resource.StateRefreshFunc {
+func clusterRefreshForCreate(ctx context.Context, meta *providerMeta, id string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 
-		requestCommand := cluster.NewGetClusterCommand(*client, id)
+		requestCommand := cluster.NewGetClusterCommand(meta.client, id)
 
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
 		if err != nil {
 			return 0, "", err
 		} else if resp.StatusDescription != nil {
@@ -517,3 +565,19 @@ func clusterRefreshForCreate(client *receiver.BMCSDK, id string) resource.StateR
 		}
 	}
 }
+
+// suppressUnreadClusterArgument hides the difference on blocks the API never returns, such as the configuration with its
+// token and certificates, while they are missing from the state of an existing cluster, e.g. after an import. Otherwise
+// the cluster would be replaced right after being imported.
+func suppressUnreadClusterArgument(k, old, new string, d *schema.ResourceData) bool {
+	if d.Id() == "" {
+		return false
+	}
+	for _, block := range []string{"configuration", "workload_configuration", "node_pools.0.ssh_config"} {
+		if strings.HasPrefix(k, block+".") {
+			prior, _ := d.GetChange(block)
+			return len(prior.([]interface{})) == 0
+		}
+	}
+	return false
+}
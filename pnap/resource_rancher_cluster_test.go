@@ -0,0 +1,122 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUnitPnapRancherCluster_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_rancher_cluster." + rName
+	var clusterID string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_rancher_cluster"),
+		Steps: []resource.TestStep{
+			{
+				// the cluster settles from Creating to Ready
+				Config: testUnitProviderConfig(api) + testUnitRancherClusterResource(rName, "unittest"),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckRancherClusterID(rLine, &clusterID, false),
+					resource.TestCheckResourceAttr(rLine, "name", rName),
+					resource.TestCheckResourceAttr(rLine, "status_description", "Ready"),
+					resource.TestCheckResourceAttr(rLine, "node_pools.0.node_count", "1"),
+					resource.TestCheckResourceAttr(rLine, "node_pools.0.nodes.#", "1"),
+					resource.TestCheckResourceAttrSet(rLine, "metadata.0.url"),
+					resource.TestCheckResourceAttrSet(rLine, "metadata.0.password"),
+				),
+			},
+			{
+				// the API can't update a cluster, so a new description replaces it
+				Config: testUnitProviderConfig(api) + testUnitRancherClusterResource(rName, "unittest-basic"),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckRancherClusterID(rLine, &clusterID, true),
+					resource.TestCheckResourceAttr(rLine, "description", "unittest-basic"),
+					resource.TestCheckResourceAttr(rLine, "status_description", "Ready"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceRancherClusterDiff_imported(t *testing.T) {
+	// an imported cluster only has the attributes the API returns in its state
+	state := &terraform.InstanceState{
+		ID: "cluster-1",
+		Attributes: map[string]string{
+			"id":                       "cluster-1",
+			"name":                     "imported",
+			"description":              "imported",
+			"location":                 "PHX",
+			"node_pools.#":             "1",
+			"node_pools.0.name":        "pool-1",
+			"node_pools.0.node_count":  "1",
+			"node_pools.0.server_type": "s0.d1.small",
+			"node_pools.0.nodes.#":     "0",
+		},
+	}
+	config := func(description string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":        "imported",
+			"description": description,
+			"location":    "PHX",
+			"node_pools": []interface{}{map[string]interface{}{
+				"name":        "pool-1",
+				"node_count":  1,
+				"server_type": "s0.d1.small",
+				"ssh_config":  []interface{}{map[string]interface{}{"keys": []interface{}{"ssh-ed25519 AAAA"}}},
+			}},
+			"configuration": []interface{}{map[string]interface{}{
+				"token":          "cluster-token",
+				"cluster_domain": "cluster.local",
+				"certificates":   []interface{}{map[string]interface{}{"ca_certificate": "-----BEGIN CERTIFICATE-----"}},
+			}},
+		})
+	}
+
+	diff, err := resourceRancherCluster().Diff(context.Background(), state, config("imported"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Errorf("imported cluster has a diff on arguments the API doesn't return: %v", diff)
+	}
+	if diff, err = resourceRancherCluster().Diff(context.Background(), state, config("changed"), nil); err != nil || !diff.RequiresNew() {
+		t.Errorf("a description change returned %v, %v, expected a replacement", diff, err)
+	}
+}
+
+func testUnitRancherClusterResource(rName, description string) string {
+	return fmt.Sprintf(`
+resource "pnap_rancher_cluster" "%s" {
+	name = "%s"
+	description = "%s"
+	location = "PHX"
+	node_pools {
+		name = "pool-1"
+		node_count = 1
+		server_type = "s0.d1.small"
+	}
+}`, rName, rName, description)
+}
+
+// testUnitCheckRancherClusterID records the cluster ID, or verifies the cluster has been replaced when replaced is set
+func testUnitCheckRancherClusterID(resourceName string, clusterID *string, replaced bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+		if replaced && rs.Primary.ID == *clusterID {
+			return fmt.Errorf("Rancher cluster (%s) has not been replaced", rs.Primary.ID)
+		}
+		*clusterID = rs.Primary.ID
+		return nil
+	}
+}
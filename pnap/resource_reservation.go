@@ -1,20 +1,21 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
+	"log"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/billingapi/reservation"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	billingapiclient "github.com/phoenixnap/go-sdk-bmc/billingapi/v4"
 )
 
 func resourceReservation() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceReservationCreate,
-		Read:   resourceReservationRead,
-		Update: resourceReservationUpdate,
-		Delete: resourceReservationDelete,
+		CreateContext: resourceReservationCreate,
+		ReadContext:   resourceReservationRead,
+		UpdateContext: resourceReservationUpdate,
+		DeleteContext: resourceReservationDelete,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -155,11 +156,14 @@ func resourceReservation() *schema.Resource {
 				},
 			},
 		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 	}
 }
 
-func resourceReservationCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceReservationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	request := &billingapiclient.ReservationRequest{}
 	request.Sku = d.Get("sku").(string)
 	if d.Get("quantity") != nil && len(d.Get("quantity").([]interface{})) > 0 {
@@ -172,27 +176,32 @@ func resourceReservationCreate(d *schema.ResourceData, m interface{}) error {
 
 		unitEnum, errorUnit := billingapiclient.NewQuantityUnitEnumFromValue(unit)
 		if errorUnit != nil {
-			return errorUnit
+			return diag.FromErr(errorUnit)
 		}
 		quantityObject.Unit = *unitEnum
 		request.Quantity = quantityObject
 	}
 	requestCommand := reservation.NewCreateReservationCommand(client, *request)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(resp.Id)
-	return resourceReservationRead(d, m)
+	return resourceReservationRead(ctx, d, m)
 }
 
-func resourceReservationRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceReservationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	reservationID := d.Id()
 	requestCommand := reservation.NewGetReservationCommand(client, reservationID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Reservation (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	d.SetId(resp.Id)
 	d.Set("product_code", resp.ProductCode)
@@ -238,9 +247,9 @@ func resourceReservationRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceReservationUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceReservationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if d.HasChange("sku") || d.HasChange("quantity") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		reservationID := d.Id()
 		request := &billingapiclient.ReservationRequest{}
 		request.Sku = d.Get("sku").(string)
@@ -254,19 +263,19 @@ func resourceReservationUpdate(d *schema.ResourceData, m interface{}) error {
 
 			unitEnum, errorUnit := billingapiclient.NewQuantityUnitEnumFromValue(unit)
 			if errorUnit != nil {
-				return errorUnit
+				return diag.FromErr(errorUnit)
 			}
 			quantityObject.Unit = *unitEnum
 			request.Quantity = quantityObject
 		}
 		requestCommand := reservation.NewConvertReservationCommand(client, reservationID, *request)
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		d.SetId(resp.Id)
 	} else if d.HasChange("auto_renew") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		newStatus := d.Get("auto_renew").(bool)
 		if !newStatus {
 			reservationID := d.Id()
@@ -276,28 +285,28 @@ func resourceReservationUpdate(d *schema.ResourceData, m interface{}) error {
 				request.AutoRenewDisableReason = &reason
 			}
 			requestCommand := reservation.NewDisableAutoRenewReservationCommand(client, reservationID, *request)
-			_, err := requestCommand.Execute()
+			_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		} else if newStatus {
 			reservationID := d.Id()
 			requestCommand := reservation.NewEnableAutoRenewReservationCommand(client, reservationID)
-			_, err := requestCommand.Execute()
+			_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		} else {
-			return fmt.Errorf("unsupported action")
+			return diag.Errorf("unsupported action")
 		}
 	} else {
-		return fmt.Errorf("unsupported action")
+		return diag.Errorf("unsupported action")
 	}
-	return resourceReservationRead(d, m)
+	return resourceReservationRead(ctx, d, m)
 }
 
-func resourceReservationDelete(d *schema.ResourceData, m interface{}) error {
-	return fmt.Errorf("unsupported action")
+func resourceReservationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return diag.Errorf("unsupported action")
 }
 
 func flattenTerm(reservationTerm *billingapiclient.ReservationTerm) []interface{} {
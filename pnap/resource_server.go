@@ -1,19 +1,25 @@
 package pnap
 
 import (
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"log"
 	"net/netip"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+	"github.com/PNAP/go-sdk-helper-bmc/command/ipapi/ipblock"
 	"github.com/PNAP/go-sdk-helper-bmc/dto"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 
 	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
 )
@@ -27,10 +33,15 @@ const (
 
 func resourceServer() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceServerCreate,
-		Read:   resourceServerRead,
-		Update: resourceServerUpdate,
-		Delete: resourceServerDelete,
+		CreateContext: resourceServerCreate,
+		ReadContext:   resourceServerRead,
+		UpdateContext: resourceServerUpdate,
+		DeleteContext: resourceServerDelete,
+		CustomizeDiff: customdiff.Sequence(
+			resourceServerCustomizeDiff,
+			resourceServerAvailabilityDiff,
+			customizeDiffTagsAll,
+		),
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -64,10 +75,13 @@ func resourceServer() *schema.Resource {
 			"os": {
 				Type:     schema.TypeString,
 				Required: true,
+				// ServerReset has no OS, a reset reinstalls the current one, so a new server is provisioned.
+				ForceNew: true,
 			},
 			"type": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"ssh_keys": {
 				Type:     schema.TypeSet,
@@ -77,6 +91,7 @@ func resourceServer() *schema.Resource {
 			"location": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"cpu": {
 				Type:     schema.TypeString,
@@ -103,6 +118,22 @@ func resourceServer() *schema.Resource {
 				Computed: true,
 			},
 			"action": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Deprecated:    "Use power_state, reboot_trigger and reinstall_trigger instead.",
+				ConflictsWith: []string{"power_state"},
+			},
+			"power_state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"powered-on", "powered-off"}, false),
+			},
+			"reboot_trigger": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"reinstall_trigger": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
@@ -110,6 +141,7 @@ func resourceServer() *schema.Resource {
 				Type:                  schema.TypeString,
 				Optional:              true,
 				Computed:              true,
+				ForceNew:              true,
 				DiffSuppressFunc:      supressUserDefinedNetworkType,
 				DiffSuppressOnRefresh: true,
 			},
@@ -137,7 +169,10 @@ func resourceServer() *schema.Resource {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIpCidrOrRange,
+				},
 			},
 			"bring_your_own_license": {
 				Type:     schema.TypeBool,
@@ -166,7 +201,10 @@ func resourceServer() *schema.Resource {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Computed: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIpCidrOrRange,
+				},
 			},
 			"install_os_to_ram": {
 				Type:     schema.TypeBool,
@@ -202,12 +240,14 @@ func resourceServer() *schema.Resource {
 				Type:     schema.TypeList,
 				Optional: true,
 				Computed: true,
+				ForceNew: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"user_data": {
 							Type:     schema.TypeString,
 							Optional: true,
+							ForceNew: true,
 						},
 					},
 				},
@@ -236,9 +276,10 @@ func resourceServer() *schema.Resource {
 										Computed: true,
 									},
 									"static_dhcp_address_v4": {
-										Type:     schema.TypeString,
-										Optional: true,
-										Computed: true,
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										ValidateFunc: validation.IsIPv4Address,
 									},
 									"status": {
 										Type:     schema.TypeString,
@@ -351,6 +392,7 @@ func resourceServer() *schema.Resource {
 					},
 				},
 			},
+			"tags_all": tagsAllSchema(),
 			"network_configuration": {
 				Type:     schema.TypeList,
 				Optional: true,
@@ -359,9 +401,10 @@ func resourceServer() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"gateway_address": {
-							Type:     schema.TypeString,
-							Computed: true,
-							Optional: true,
+							Type:         schema.TypeString,
+							Computed:     true,
+							Optional:     true,
+							ValidateFunc: validation.IsIPAddress,
 						},
 						"private_network_configuration": {
 							Type:     schema.TypeList,
@@ -371,9 +414,10 @@ func resourceServer() *schema.Resource {
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"gateway_address": { //Deprecated
-										Type:     schema.TypeString,
-										Optional: true,
-										Computed: true,
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										ValidateFunc: validation.IsIPAddress,
 									},
 									"configuration_type": {
 										Type:     schema.TypeString,
@@ -402,7 +446,10 @@ func resourceServer() *schema.Resource {
 																Type:     schema.TypeSet,
 																Optional: true,
 																Computed: true,
-																Elem:     &schema.Schema{Type: schema.TypeString},
+																Elem: &schema.Schema{
+																	Type:         schema.TypeString,
+																	ValidateFunc: validateIpOrRange,
+																},
 															},
 															"dhcp": {
 																Type:     schema.TypeBool,
@@ -497,7 +544,10 @@ func resourceServer() *schema.Resource {
 															"ips": {
 																Type:     schema.TypeSet,
 																Required: true,
-																Elem:     &schema.Schema{Type: schema.TypeString},
+																Elem: &schema.Schema{
+																	Type:         schema.TypeString,
+																	ValidateFunc: validateIpOrRange,
+																},
 															},
 															"status_description": {
 																Type:     schema.TypeString,
@@ -526,23 +576,30 @@ func resourceServer() *schema.Resource {
 			"storage_configuration": {
 				Type:     schema.TypeList,
 				Optional: true,
+				Computed: true,
+				ForceNew: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"root_partition": {
 							Type:     schema.TypeList,
 							Optional: true,
+							Computed: true,
+							ForceNew: true,
 							MaxItems: 1,
 							Elem: &schema.Resource{
 								Schema: map[string]*schema.Schema{
 									"raid": {
-										Type:     schema.TypeString,
-										Optional: true,
-										Default:  "NO_RAID",
+										Type:         schema.TypeString,
+										Optional:     true,
+										ForceNew:     true,
+										Default:      "NO_RAID",
+										ValidateFunc: validation.StringInSlice([]string{"NO_RAID", "RAID_0", "RAID_1"}, false),
 									},
 									"size": {
 										Type:     schema.TypeInt,
 										Optional: true,
+										ForceNew: true,
 										Default:  -1,
 									},
 								},
@@ -576,12 +633,15 @@ func resourceServer() *schema.Resource {
 				Computed: true,
 			},
 		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerImport,
+		},
 	}
 }
 
-func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
+func resourceServerCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &bmcapiclient.ServerCreate{}
 	request.Hostname = d.Get("hostname").(string)
@@ -756,6 +816,7 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 		}
 		request.Tags = tagsObject
 	}
+	request.Tags = mergeDefaultTags(request.Tags, m.(*providerMeta).defaultTags)
 
 	query := &dto.Query{}
 	var force = d.Get("force").(bool)
@@ -949,9 +1010,9 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 
 	requestCommand := server.NewCreateServerCommandWithQuery(client, *request, query)
 
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	} else {
 
 		d.SetId(resp.Id)
@@ -976,22 +1037,34 @@ func resourceServerCreate(d *schema.ResourceData, m interface{}) error {
 			d.Set("netris_controller", netrisController)
 		}
 
-		waitResultError := resourceWaitForCreate(resp.Id, &client)
+		waitResultError := resourceWaitForCreate(ctx, resp.Id, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 		if waitResultError != nil {
-			return waitResultError
+			return diag.FromErr(waitResultError)
+		}
+
+		if d.Get("power_state").(string) == "powered-off" {
+			diags := resourceServerSetPowerState(ctx, d, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
+			if diags.HasError() {
+				return diags
+			}
 		}
 	}
 
-	return resourceServerRead(d, m)
+	return resourceServerRead(ctx, d, m)
 }
 
-func resourceServerRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceServerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	serverID := d.Id()
 	requestCommand := server.NewGetServerCommand(client, serverID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Server (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
 	d.Set("status", resp.Status)
@@ -1008,6 +1081,9 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("storage", resp.Storage)
 	d.Set("network_type", resp.NetworkType)
 	d.Set("action", "")
+	if resp.Status == "powered-on" || resp.Status == "powered-off" {
+		d.Set("power_state", resp.Status)
+	}
 	var privateIPs []interface{}
 	for _, v := range resp.PrivateIpAddresses {
 		privateIPs = append(privateIPs, v)
@@ -1108,17 +1184,23 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 
 	if resp.Tags != nil && len(resp.Tags) > 0 {
 		var tagsInput = d.Get("tags").([]interface{})
-		tags := flattenServerTags(resp.Tags, tagsInput)
+		tags := flattenServerTags(resp.Tags, tagsInput, m.(*providerMeta).defaultTags)
 		if err := d.Set("tags", tags); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
+	if err := d.Set("tags_all", flattenTagsAll(resp.Tags)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("storage_configuration", flattenStorageConfiguration(resp.StorageConfiguration, d.Get("storage_configuration").([]interface{}))); err != nil {
+		return diag.FromErr(err)
+	}
 
 	var ncInput = d.Get("network_configuration").([]interface{})
 	networkConfiguration := flattenNetworkConfiguration(&resp.NetworkConfiguration, ncInput)
 
 	if err := d.Set("network_configuration", networkConfiguration); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	var gpuConf bmcapiclient.GpuConfiguration
@@ -1133,184 +1215,94 @@ func resourceServerRead(d *schema.ResourceData, m interface{}) error {
 
 	return nil
 }
-func resourceServerUpdate(d *schema.ResourceData, m interface{}) error {
-	if d.HasChange("action") {
-		client := m.(receiver.BMCSDK)
-		//var requestCommand helpercommand.Executor
-		newStatus := d.Get("action").(string)
-
-		switch newStatus {
-		case "powered-on":
-			//do power-on request
-			serverID := d.Id()
-			requestCommand := server.NewPowerOnServerCommand(client, serverID)
-			_, err := requestCommand.Execute()
-			if err != nil {
-				return err
-			}
-			waitResultError := resourceWaitForPowerON(d.Id(), &client)
-			if waitResultError != nil {
-				return waitResultError
-			}
-		case "powered-off":
-			//power off request
 
-			serverID := d.Id()
+// resourceServerUpdateSteps are the attributes resourceServerUpdate applies, in order.
+var resourceServerUpdateSteps = []string{"reinstall_trigger", "hostname", "description", "tags", "tags_all", "ipxe", "pricing_model", "transfer_reservation_to", "action", "reboot_trigger", "power_state"}
 
-			requestCommand := server.NewPowerOffServerCommand(client, serverID)
-			_, err := requestCommand.Execute()
-			if err != nil {
-				return err
-			}
-			waitResultError := resourceWaitForPowerOff(d.Id(), &client)
-			if waitResultError != nil {
-				return waitResultError
-			}
-		case "reboot":
-			//reboot
-
-			serverID := d.Id()
-			isIPXE := strings.Contains(d.Get("os").(string), "ipxe")
-			rebootRequest := &bmcapiclient.RebootRequest{}
-			bootType := "STANDARD"
-			if isIPXE {
-				bootType = "IPXE"
-				if d.Get("ipxe") != nil && len(d.Get("ipxe").([]interface{})) > 0 {
-					iPXE := d.Get("ipxe").([]interface{})[0]
-					iPXEItem := iPXE.(map[string]interface{})
-					if len(iPXEItem["url"].(string)) > 0 {
-						url1 := iPXEItem["url"].(string)
-						ipxeUrl := bmcapiclient.NullableString{}
-						ipxeUrl.Set(&url1)
-						rebootRequest.IpxeUrl = ipxeUrl
-					}
-				}
-			}
-			rebootRequest.BootType = &bootType
+func resourceServerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChangesExcept("hostname", "description", "tags", "tags_all", "ipxe", "pricing_model", "transfer_reservation_to", "action", "delete_ip_blocks", "force", "power_state", "reboot_trigger", "reinstall_trigger") {
+		return diag.Errorf("unsupported action")
+	}
+	var diags diag.Diagnostics
+	client := m.(*providerMeta).client
+	serverID := d.Id()
 
-			requestCommand := server.NewRebootServerCommand(client, serverID, *rebootRequest)
-			_, err := requestCommand.Execute()
-			if err != nil {
-				return err
-			}
-			waitResultError := resourceWaitForCreate(d.Id(), &client)
-			if waitResultError != nil {
-				return waitResultError
-			}
-		case "reset": //Deprecated
-			//reset
-			request := &bmcapiclient.ServerReset{}
-			temp := d.Get("ssh_keys").(*schema.Set).List()
-			keys := make([]string, len(temp))
-			for i, v := range temp {
-				keys[i] = fmt.Sprint(v)
+	// Changes are applied one by one in a fixed order. When a step fails, the SDK saves the planned values,
+	// so fail resets the attributes of the steps that didn't go through to their prior values. The state
+	// then records what was applied, e.g. the password of a reinstall that preceded the failure.
+	applied := make(map[string]bool)
+	fail := func(failure diag.Diagnostics) diag.Diagnostics {
+		for _, k := range resourceServerUpdateSteps {
+			if !applied[k] && d.HasChange(k) {
+				old, _ := d.GetChange(k)
+				d.Set(k, old)
 			}
-			request.SshKeys = keys
-			var installDefault = d.Get("install_default_ssh_keys").(bool)
-			request.InstallDefaultSshKeys = &installDefault
-
-			temp1 := d.Get("ssh_key_ids").(*schema.Set).List()
-			keyIds := make([]string, len(temp1))
-			for i, v := range temp1 {
-				keyIds[i] = fmt.Sprint(v)
-			}
-			request.SshKeyIds = keyIds
-
-			dtoOsConfiguration := bmcapiclient.OsConfigurationMap{}
-			isWindows := strings.Contains(d.Get("os").(string), "windows")
-			isEsxi := strings.Contains(d.Get("os").(string), "esxi")
-
-			if isWindows {
-				//log.Printf("Waiting for server windows to be reseted...")
-				dtoWindows := bmcapiclient.OsConfigurationWindows{}
-				temp2 := d.Get("rdp_allowed_ips").(*schema.Set).List()
-				allowedIps := make([]string, len(temp2))
-				for i, v := range temp2 {
-					allowedIps[i] = fmt.Sprint(v)
-				}
+		}
+		return append(diags, failure...)
+	}
 
-				dtoWindows.RdpAllowedIps = allowedIps
-				dtoOsConfiguration.Windows = &dtoWindows
-				dtoOsConfiguration.Esxi = nil
-				request.OsConfiguration = &dtoOsConfiguration
-			}
+	if d.HasChange("reinstall_trigger") {
+		request := expandServerReset(d)
+		// Once the reset is accepted the server is wiped, it mustn't be reinstalled again if waiting fails.
+		reset, reinstallDiags := resourceServerReinstall(ctx, d, m.(*providerMeta), request)
+		applied["reinstall_trigger"] = reset
+		if reinstallDiags.HasError() {
+			return fail(reinstallDiags)
+		}
+		diags = append(diags, reinstallDiags...)
+	}
 
-			if isEsxi {
-				//log.Printf("Waiting for server esxi to be reseted...")
-				dtoEsxi := bmcapiclient.OsConfigurationMapEsxi{}
-				temp3 := d.Get("management_access_allowed_ips").(*schema.Set).List()
-				managementAccessAllowedIps := make([]string, len(temp3))
-				for i, v := range temp3 {
-					managementAccessAllowedIps[i] = fmt.Sprint(v)
-				}
-				dtoEsxi.ManagementAccessAllowedIps = managementAccessAllowedIps
-				dtoOsConfiguration.Esxi = &dtoEsxi
-				dtoOsConfiguration.Windows = nil
-				request.OsConfiguration = &dtoOsConfiguration
+	if d.HasChange("hostname") || d.HasChange("description") {
+		request := &bmcapiclient.ServerPatch{}
+		var hostname = d.Get("hostname").(string)
+		request.Hostname = &hostname
+		var desc = d.Get("description").(string)
+		request.Description = &desc
+		requestCommand := server.NewPatchServerCommand(client, serverID, *request)
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+		if err != nil {
+			return fail(diag.FromErr(err))
+		}
+		d.Set("hostname", hostname)
+		d.Set("description", desc)
+		applied["hostname"], applied["description"] = true, true
+	}
 
-			}
-			requestCommand := server.NewResetServerCommand(client, d.Id(), *request)
-			resp, err := requestCommand.Execute()
-			if err != nil {
-				return err
-			}
-			d.Set("password", resp.Password)
+	if d.HasChanges("tags", "tags_all") {
+		tags := d.Get("tags").([]interface{})
 
-			if resp.OsConfiguration != nil && resp.OsConfiguration.Esxi != nil {
-				d.Set("root_password", resp.OsConfiguration.Esxi.RootPassword)
-				d.Set("management_ui_url", resp.OsConfiguration.Esxi.ManagementUiUrl)
-			}
+		var request []bmcapiclient.TagAssignmentRequest
 
-			waitResultError := resourceWaitForCreate(d.Id(), &client)
-			if waitResultError != nil {
-				return waitResultError
-			}
+		if len(tags) > 0 {
+			request = make([]bmcapiclient.TagAssignmentRequest, len(tags))
 
-		case "shutdown":
+			for i, j := range tags {
+				tarObject := bmcapiclient.TagAssignmentRequest{}
+				tagsItem := j.(map[string]interface{})
 
-			serverID := d.Id()
+				tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
+				tagAssignItem := tagAssign.(map[string]interface{})
 
-			requestCommand := server.NewShutDownServerCommand(client, serverID)
-			_, err := requestCommand.Execute()
-			if err != nil {
-				return err
-			}
-			waitResultError := resourceWaitForPowerOff(d.Id(), &client)
-			if waitResultError != nil {
-				return waitResultError
+				tarObject.Name = tagAssignItem["name"].(string)
+				value := tagAssignItem["value"].(string)
+				if len(value) > 0 {
+					tarObject.Value = &value
+				}
+				request[i] = tarObject
 			}
-
-		case "default":
-			return fmt.Errorf("unsupported action")
 		}
-
-	} else if d.HasChange("pricing_model") {
-		client := m.(receiver.BMCSDK)
-		//var requestCommand command.Executor
-		//reserve action
-		request := &bmcapiclient.ServerReserve{}
-		//request.Id = d.Id()
-		request.PricingModel = d.Get("pricing_model").(string)
-
-		requestCommand := server.NewReserveServerCommand(client, d.Id(), *request)
-		_, err := requestCommand.Execute()
+		request = mergeDefaultTags(request, m.(*providerMeta).defaultTags)
+		requestCommand := server.NewSetServerTagsCommand(client, serverID, request)
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return fail(diag.FromErr(err))
 		}
-	} else if d.HasChange("transfer_reservation_to") {
-		client := m.(receiver.BMCSDK)
-		request := &bmcapiclient.ReservationTransferDetails{}
-		serverID := d.Id()
-		request.TargetServerId = d.Get("transfer_reservation_to").(string)
+		d.Set("tags", tags)
+		applied["tags"] = true
+		applied["tags_all"] = true
+	}
 
-		requestCommand := server.NewTransferServerReservationCommand(client, serverID, *request)
-		_, err := requestCommand.Execute()
-		if err != nil {
-			return err
-		}
-	} else if d.HasChange("ipxe") {
-		client := m.(receiver.BMCSDK)
-		serverID := d.Id()
+	if d.HasChange("ipxe") {
 		request := &bmcapiclient.OsConfigurationIPXE{}
 		nativeVlanConfObject := bmcapiclient.OsConfigurationIPXENativeVlanConfiguration{}
 		if d.Get("ipxe") != nil && len(d.Get("ipxe").([]interface{})) > 0 {
@@ -1335,64 +1327,285 @@ func resourceServerUpdate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		requestCommand := server.NewUpdateServerIPXECommand(client, serverID, *request)
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return fail(diag.FromErr(err))
 		}
-	} else if d.HasChange("tags") {
-		tags := d.Get("tags").([]interface{})
-		client := m.(receiver.BMCSDK)
-		serverID := d.Id()
+		applied["ipxe"] = true
+	}
 
-		var request []bmcapiclient.TagAssignmentRequest
+	if d.HasChange("pricing_model") {
+		//reserve action
+		request := &bmcapiclient.ServerReserve{}
+		request.PricingModel = d.Get("pricing_model").(string)
 
-		if len(tags) > 0 {
-			request = make([]bmcapiclient.TagAssignmentRequest, len(tags))
+		requestCommand := server.NewReserveServerCommand(client, serverID, *request)
+		_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
+		if err != nil {
+			return fail(diag.FromErr(err))
+		}
+		applied["pricing_model"] = true
+	}
 
-			for i, j := range tags {
-				tarObject := bmcapiclient.TagAssignmentRequest{}
-				tagsItem := j.(map[string]interface{})
+	if d.HasChange("transfer_reservation_to") {
+		request := &bmcapiclient.ReservationTransferDetails{}
+		request.TargetServerId = d.Get("transfer_reservation_to").(string)
 
-				tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
-				tagAssignItem := tagAssign.(map[string]interface{})
+		requestCommand := server.NewTransferServerReservationCommand(client, serverID, *request)
+		_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
+		if err != nil {
+			return fail(diag.FromErr(err))
+		}
+		applied["transfer_reservation_to"] = true
+	}
 
-				tarObject.Name = tagAssignItem["name"].(string)
-				value := tagAssignItem["value"].(string)
-				if len(value) > 0 {
-					tarObject.Value = &value
-				}
-				request[i] = tarObject
+	if d.HasChange("action") {
+		actionDiags := resourceServerPowerAction(ctx, d, m.(*providerMeta))
+		if actionDiags.HasError() {
+			return fail(actionDiags)
+		}
+		diags = append(diags, actionDiags...)
+		applied["action"] = true
+	}
+
+	if d.HasChange("reboot_trigger") {
+		rebootDiags := resourceServerReboot(ctx, d, m.(*providerMeta))
+		if rebootDiags.HasError() {
+			return fail(rebootDiags)
+		}
+		applied["reboot_trigger"] = true
+	}
+
+	if d.HasChange("power_state") {
+		powerDiags := resourceServerSetPowerState(ctx, d, m.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
+		if powerDiags.HasError() {
+			return fail(powerDiags)
+		}
+		applied["power_state"] = true
+	}
+
+	return append(diags, resourceServerRead(ctx, d, m)...)
+}
+
+// resourceServerPowerAction executes the power action requested through the action argument and waits for its outcome.
+func resourceServerPowerAction(ctx context.Context, d *schema.ResourceData, meta *providerMeta) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := meta.client
+	serverID := d.Id()
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	newStatus := d.Get("action").(string)
+
+	switch newStatus {
+	case "powered-on":
+		//do power-on request
+		requestCommand := server.NewPowerOnServerCommand(client, serverID)
+		_, err := execute(ctx, meta, retryThrottledOnly, requestCommand.Execute)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		waitResultError := resourceWaitForPowerON(ctx, serverID, meta, timeout)
+		if waitResultError != nil {
+			return diag.FromErr(waitResultError)
+		}
+	case "powered-off":
+		//power off request
+		requestCommand := server.NewPowerOffServerCommand(client, serverID)
+		_, err := execute(ctx, meta, retryThrottledOnly, requestCommand.Execute)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		waitResultError := resourceWaitForPowerOff(ctx, serverID, meta, timeout)
+		if waitResultError != nil {
+			return diag.FromErr(waitResultError)
+		}
+	case "reboot":
+		diags = append(diags, resourceServerReboot(ctx, d, meta)...)
+		if diags.HasError() {
+			return diags
+		}
+	case "reset": //Deprecated
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "Deprecated server action",
+			Detail:        "The reset action is deprecated and will be removed in a future release, use reinstall_trigger instead.",
+			AttributePath: cty.GetAttrPath("action"),
+		})
+		_, reinstallDiags := resourceServerReinstall(ctx, d, meta, expandServerReset(d))
+		diags = append(diags, reinstallDiags...)
+		if diags.HasError() {
+			return diags
+		}
+
+	case "shutdown":
+		requestCommand := server.NewShutDownServerCommand(client, serverID)
+		_, err := execute(ctx, meta, retryThrottledOnly, requestCommand.Execute)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		waitResultError := resourceWaitForPowerOff(ctx, serverID, meta, timeout)
+		if waitResultError != nil {
+			return diag.FromErr(waitResultError)
+		}
+
+	default:
+		return diag.Errorf("unsupported action")
+	}
+	return diags
+}
+
+// resourceServerReboot reboots the server, into iPXE for the custom/ipxe OS, and waits for it to come back.
+func resourceServerReboot(ctx context.Context, d *schema.ResourceData, meta *providerMeta) diag.Diagnostics {
+	isIPXE := strings.Contains(d.Get("os").(string), "ipxe")
+	rebootRequest := &bmcapiclient.RebootRequest{}
+	bootType := "STANDARD"
+	if isIPXE {
+		bootType = "IPXE"
+		if d.Get("ipxe") != nil && len(d.Get("ipxe").([]interface{})) > 0 {
+			iPXE := d.Get("ipxe").([]interface{})[0]
+			iPXEItem := iPXE.(map[string]interface{})
+			if len(iPXEItem["url"].(string)) > 0 {
+				url1 := iPXEItem["url"].(string)
+				ipxeUrl := bmcapiclient.NullableString{}
+				ipxeUrl.Set(&url1)
+				rebootRequest.IpxeUrl = ipxeUrl
 			}
 		}
-		requestCommand := server.NewSetServerTagsCommand(client, serverID, request)
-		_, err := requestCommand.Execute()
+	}
+	rebootRequest.BootType = &bootType
+
+	requestCommand := server.NewRebootServerCommand(meta.client, d.Id(), *rebootRequest)
+	_, err := execute(ctx, meta, retryThrottledOnly, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	waitResultError := resourceWaitForCreate(ctx, d.Id(), meta, d.Timeout(schema.TimeoutUpdate))
+	if waitResultError != nil {
+		return diag.FromErr(waitResultError)
+	}
+	return nil
+}
+
+// resourceServerSetPowerState brings the server to the configured power_state and waits for it to get there.
+func resourceServerSetPowerState(ctx context.Context, d *schema.ResourceData, meta *providerMeta, timeout time.Duration) diag.Diagnostics {
+	serverID := d.Id()
+	switch d.Get("power_state").(string) {
+	case "powered-on":
+		requestCommand := server.NewPowerOnServerCommand(meta.client, serverID)
+		_, err := execute(ctx, meta, retryThrottledOnly, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-	} else if d.HasChange("hostname") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
-		serverID := d.Id()
-		request := &bmcapiclient.ServerPatch{}
-		var hostname = d.Get("hostname").(string)
-		request.Hostname = &hostname
-		var desc = d.Get("description").(string)
-		request.Description = &desc
-		requestCommand := server.NewPatchServerCommand(client, serverID, *request)
-		_, err := requestCommand.Execute()
+		waitResultError := resourceWaitForPowerON(ctx, serverID, meta, timeout)
+		if waitResultError != nil {
+			return diag.FromErr(waitResultError)
+		}
+	case "powered-off":
+		requestCommand := server.NewShutDownServerCommand(meta.client, serverID)
+		_, err := execute(ctx, meta, retryThrottledOnly, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-	} else if d.HasChange("delete_ip_blocks") || d.HasChange("force") {
-		return resourceServerRead(d, m)
-	} else {
-		return fmt.Errorf("unsupported action")
+		waitResultError := resourceWaitForPowerOff(ctx, serverID, meta, timeout)
+		if waitResultError != nil {
+			return diag.FromErr(waitResultError)
+		}
+	}
+	d.Set("power_state", d.Get("power_state"))
+	return nil
+}
+
+// expandServerReset builds the reinstall request from the SSH keys and the OS configuration of the server.
+func expandServerReset(d *schema.ResourceData) bmcapiclient.ServerReset {
+	request := bmcapiclient.ServerReset{}
+	temp := d.Get("ssh_keys").(*schema.Set).List()
+	keys := make([]string, len(temp))
+	for i, v := range temp {
+		keys[i] = fmt.Sprint(v)
 	}
-	return resourceServerRead(d, m)
+	request.SshKeys = keys
+	var installDefault = d.Get("install_default_ssh_keys").(bool)
+	request.InstallDefaultSshKeys = &installDefault
+
+	temp1 := d.Get("ssh_key_ids").(*schema.Set).List()
+	keyIds := make([]string, len(temp1))
+	for i, v := range temp1 {
+		keyIds[i] = fmt.Sprint(v)
+	}
+	request.SshKeyIds = keyIds
+
+	temp2 := d.Get("rdp_allowed_ips").(*schema.Set).List()
+	allowedIps := make([]string, len(temp2))
+	for i, v := range temp2 {
+		allowedIps[i] = fmt.Sprint(v)
+	}
+	bringLicense := d.Get("bring_your_own_license").(bool)
 
+	temp3 := d.Get("management_access_allowed_ips").(*schema.Set).List()
+	managementAccessAllowedIps := make([]string, len(temp3))
+	for i, v := range temp3 {
+		managementAccessAllowedIps[i] = fmt.Sprint(v)
+	}
+
+	osID := d.Get("os").(string)
+	dtoOsConfiguration := bmcapiclient.OsConfigurationMap{}
+	if strings.HasPrefix(osID, "windows/") {
+		dtoWindows := bmcapiclient.OsConfigurationWindows{}
+		if len(allowedIps) > 0 {
+			dtoWindows.RdpAllowedIps = allowedIps
+		}
+		dtoWindows.BringYourOwnLicense = &bringLicense
+		dtoOsConfiguration.Windows = &dtoWindows
+	}
+	if strings.HasPrefix(osID, "esxi/") && len(managementAccessAllowedIps) > 0 {
+		dtoOsConfiguration.Esxi = &bmcapiclient.OsConfigurationMapEsxi{ManagementAccessAllowedIps: managementAccessAllowedIps}
+	}
+	if strings.HasPrefix(osID, "proxmox/") && len(managementAccessAllowedIps) > 0 {
+		dtoOsConfiguration.Proxmox = &bmcapiclient.OsConfigurationMapProxmox{ManagementAccessAllowedIps: managementAccessAllowedIps}
+	}
+	if dtoOsConfiguration.Windows != nil || dtoOsConfiguration.Esxi != nil || dtoOsConfiguration.Proxmox != nil {
+		request.OsConfiguration = &dtoOsConfiguration
+	}
+	return request
 }
 
-func resourceServerDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+// resourceServerReinstall resets the server with the given request, which reinstalls its OS, and waits for it to come back.
+// It reports whether the reset was accepted, even if waiting for the server failed afterwards.
+func resourceServerReinstall(ctx context.Context, d *schema.ResourceData, meta *providerMeta, request bmcapiclient.ServerReset) (bool, diag.Diagnostics) {
+	requestCommand := server.NewResetServerCommand(meta.client, d.Id(), request)
+	resp, err := execute(ctx, meta, retryThrottledOnly, requestCommand.Execute)
+	if err != nil {
+		return false, diag.FromErr(err)
+	}
+	d.Set("password", resp.Password)
+
+	if resp.OsConfiguration != nil && resp.OsConfiguration.Esxi != nil {
+		d.Set("root_password", resp.OsConfiguration.Esxi.RootPassword)
+		d.Set("management_ui_url", resp.OsConfiguration.Esxi.ManagementUiUrl)
+	}
+	if resp.OsConfiguration != nil && resp.OsConfiguration.Proxmox != nil {
+		d.Set("root_password", resp.OsConfiguration.Proxmox.RootPassword)
+		d.Set("management_ui_url", resp.OsConfiguration.Proxmox.ManagementUiUrl)
+	}
+
+	var diags diag.Diagnostics
+	if len(d.Get("cloud_init.0.user_data").(string)) > 0 || d.Get("install_os_to_ram").(bool) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Reinstall without cloud_init and install_os_to_ram",
+			Detail: fmt.Sprintf("Server %s was reinstalled without its cloud_init user data and install_os_to_ram setting, "+
+				"the reset API doesn't support them. Replace the server to provision it with them again.", d.Id()),
+		})
+	}
+
+	waitResultError := resourceWaitForCreate(ctx, d.Id(), meta, d.Timeout(schema.TimeoutUpdate))
+	if waitResultError != nil {
+		return true, append(diags, diag.FromErr(waitResultError)...)
+	}
+	return true, diags
+}
+
+func resourceServerDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	serverID := d.Id()
 
 	var deleteIpBlocks = d.Get("delete_ip_blocks").(bool)
@@ -1400,29 +1613,160 @@ func resourceServerDelete(d *schema.ResourceData, m interface{}) error {
 	relinquishIpBlock := bmcapiclient.RelinquishIpBlock{}
 	relinquishIpBlock.DeleteIpBlocks = &deleteIpBlocks
 
+	// the blocks are read from state before they vanish along with the server
+	ipBlockIDs := serverIpBlockIDs(d)
+
 	requestCommand := server.NewDeprovisionServerCommand(client, serverID, relinquishIpBlock)
 
-	_, err := requestCommand.Execute()
+	_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 
-	return nil
+	waitResultError := resourceWaitForDelete(ctx, serverID, m.(*providerMeta), d.Timeout(schema.TimeoutDelete))
+	if waitResultError != nil {
+		return diag.FromErr(waitResultError)
+	}
+
+	var diags diag.Diagnostics
+	if deleteIpBlocks {
+		for _, ipBlockID := range ipBlockIDs {
+			ipBlockCommand := ipblock.NewGetIpBlockCommand(client, ipBlockID)
+			ipBlock, err := execute(ctx, m.(*providerMeta), retryIdempotent, ipBlockCommand.Execute)
+			if err != nil {
+				if isNotFound(err) {
+					continue
+				}
+				return append(diags, diag.FromErr(err)...)
+			}
+			status := ""
+			if ipBlock.Status != nil {
+				status = *ipBlock.Status
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Warning,
+				Summary:       "IP block not relinquished",
+				Detail:        fmt.Sprintf("IP block %s is still present with status %q after server %s was deprovisioned.", ipBlockID, status, serverID),
+				AttributePath: cty.GetAttrPath("delete_ip_blocks"),
+			})
+		}
+	}
+
+	return diags
 }
 
-func resourceWaitForCreate(id string, client *receiver.BMCSDK) error {
+// serverIpBlockIDs returns the IDs of the IP blocks assigned to the server as recorded in state.
+func serverIpBlockIDs(d *schema.ResourceData) []string {
+	var ids []string
+	networkConfiguration := d.Get("network_configuration").([]interface{})
+	if len(networkConfiguration) == 0 || networkConfiguration[0] == nil {
+		return ids
+	}
+	ipBlocksConfiguration := networkConfiguration[0].(map[string]interface{})["ip_blocks_configuration"].([]interface{})
+	if len(ipBlocksConfiguration) == 0 || ipBlocksConfiguration[0] == nil {
+		return ids
+	}
+	ipBlocks := ipBlocksConfiguration[0].(map[string]interface{})["ip_blocks"].([]interface{})
+	for _, j := range ipBlocks {
+		if j == nil {
+			continue
+		}
+		serverIpBlock := j.(map[string]interface{})["server_ip_block"].([]interface{})
+		if len(serverIpBlock) == 0 || serverIpBlock[0] == nil {
+			continue
+		}
+		id := serverIpBlock[0].(map[string]interface{})["id"].(string)
+		if len(id) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// resourceServerImport populates the nested blocks that read only refreshes from existing configuration.
+func resourceServerImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*providerMeta).client
+	requestCommand := server.NewGetServerCommand(client, d.Id())
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("install_default_ssh_keys", true)
+
+	defaultTags := m.(*providerMeta).defaultTags
+	if len(resp.Tags) > 0 {
+		tags := make([]interface{}, 0, len(resp.Tags))
+		for _, j := range resp.Tags {
+			if isDefaultTag(j.Name, defaultTags) {
+				continue
+			}
+			tagsItem := make(map[string]interface{})
+			tagAssign := make([]interface{}, 1)
+			tagAssignItem := make(map[string]interface{})
+			tagAssignItem["name"] = j.Name
+			if j.Value != nil {
+				tagAssignItem["value"] = *j.Value
+			}
+			tagAssign[0] = tagAssignItem
+			tagsItem["tag_assignment"] = tagAssign
+			tags = append(tags, tagsItem)
+		}
+		if err := d.Set("tags", tags); err != nil {
+			return nil, err
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// flattenStorageConfiguration returns the storage_configuration block, empty when the API doesn't report a root partition.
+// The API reports the partition it created, the raid and size defaults of scInput are kept so they don't replace the server.
+func flattenStorageConfiguration(storageConfiguration bmcapiclient.StorageConfiguration, scInput []interface{}) []interface{} {
+	if storageConfiguration.RootPartition == nil {
+		return make([]interface{}, 0)
+	}
+	rootPartitionInput := make(map[string]interface{})
+	if len(scInput) > 0 && scInput[0] != nil {
+		rpInput := scInput[0].(map[string]interface{})["root_partition"].([]interface{})
+		if len(rpInput) > 0 && rpInput[0] != nil {
+			rootPartitionInput = rpInput[0].(map[string]interface{})
+		}
+	}
+	rootPartitionItem := make(map[string]interface{})
+	if storageConfiguration.RootPartition.Raid != nil {
+		rootPartitionItem["raid"] = *storageConfiguration.RootPartition.Raid
+	}
+	if raid, ok := rootPartitionInput["raid"]; ok && raid == "NO_RAID" {
+		rootPartitionItem["raid"] = raid
+	}
+	if storageConfiguration.RootPartition.Size != nil {
+		rootPartitionItem["size"] = int(*storageConfiguration.RootPartition.Size)
+	}
+	if size, ok := rootPartitionInput["size"]; ok && size == -1 {
+		rootPartitionItem["size"] = size
+	}
+	storageConfigurationItem := map[string]interface{}{"root_partition": []interface{}{rootPartitionItem}}
+	return []interface{}{storageConfigurationItem}
+}
+
+func resourceWaitForCreate(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
 	log.Printf("Waiting for server %s to be created...", id)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"creating", "resetting", "rebooting"},
-		Target:     []string{"powered-on", "powered-off"},
-		Refresh:    refreshForCreate(client, id),
-		Timeout:    pnapRetryTimeout,
-		Delay:      pnapRetryDelay,
-		MinTimeout: pnapRetryMinTimeout,
+		Pending:      []string{"creating", "resetting", "rebooting"},
+		Target:       []string{"powered-on", "powered-off"},
+		Refresh:      refreshForCreate(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
 	}
 
-	_, err := stateConf.WaitForState()
+	_, err := stateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Error waiting for server (%s) to switch to target state: %v", id, err)
 	}
@@ -1430,19 +1774,20 @@ func resourceWaitForCreate(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
-func resourceWaitForPowerON(id string, client *receiver.BMCSDK) error {
+func resourceWaitForPowerON(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
 	log.Printf("Waiting for server %s to power on...", id)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"powered-off"},
-		Target:     []string{"powered-on"},
-		Refresh:    refreshForCreate(client, id),
-		Timeout:    pnapRetryTimeout,
-		Delay:      pnapRetryDelay,
-		MinTimeout: pnapRetryMinTimeout,
+		Pending:      []string{"powered-off"},
+		Target:       []string{"powered-on"},
+		Refresh:      refreshForCreate(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
 	}
 
-	_, err := stateConf.WaitForState()
+	_, err := stateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Error waiting for server (%s) to power on: %v", id, err)
 	}
@@ -1450,19 +1795,20 @@ func resourceWaitForPowerON(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
-func resourceWaitForPowerOff(id string, client *receiver.BMCSDK) error {
+func resourceWaitForPowerOff(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
 	log.Printf("Waiting for server %s to power off...", id)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"powered-on"},
-		Target:     []string{"powered-off"},
-		Refresh:    refreshForCreate(client, id),
-		Timeout:    pnapRetryTimeout,
-		Delay:      pnapRetryDelay,
-		MinTimeout: pnapRetryMinTimeout,
+		Pending:      []string{"powered-on"},
+		Target:       []string{"powered-off"},
+		Refresh:      refreshForCreate(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
 	}
 
-	_, err := stateConf.WaitForState()
+	_, err := stateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Error waiting for server (%s) to power off: %v", id, err)
 	}
@@ -1470,12 +1816,50 @@ func resourceWaitForPowerOff(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
-func refreshForCreate(client *receiver.BMCSDK, id string) resource.StateRefreshFunc {
+func resourceWaitForDelete(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
+	log.Printf("Waiting for server %s to be deprovisioned...", id)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"deleting"},
+		Target:       []string{"deleted"},
+		Refresh:      refreshForDelete(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("Error waiting for server (%s) to be deprovisioned: %v", id, err)
+	}
+
+	return nil
+}
+
+// refreshForDelete reports the server as deleted once the API no longer finds it.
+func refreshForDelete(ctx context.Context, meta *providerMeta, id string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+
+		requestCommand := server.NewGetServerCommand(meta.client, id)
+
+		_, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
+		if err != nil {
+			if isNotFound(err) {
+				return 0, "deleted", nil
+			}
+			return 0, "", err
+		}
+		return 0, "deleting", nil
+	}
+}
+
+func refreshForCreate(ctx context.Context, meta *providerMeta, id string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 
-		requestCommand := server.NewGetServerCommand(*client, id)
+		requestCommand := server.NewGetServerCommand(meta.client, id)
 
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
 		if err != nil {
 			return 0, "", err
 		} else {
@@ -1618,27 +2002,44 @@ func flattenNetworkConfiguration(netConf *bmcapiclient.NetworkConfiguration, ncI
 						ibc = make([]interface{}, 1)
 						ibci := make(map[string]interface{})
 						ibc.([]interface{})[0] = ibci
+						nciMap["ip_blocks_configuration"] = ibc
 					}
 
 					ibci := ibc.([]interface{})[0]
 					ibcInput := ibci.(map[string]interface{})
 
 					ipBlocks := ipBlocksConf.IpBlocks
-					ib := make([]interface{}, len(ipBlocks))
-					for i, j := range ipBlocks {
-						ibItem := make(map[string]interface{})
-						sib := make([]interface{}, 1)
-						sibItem := make(map[string]interface{})
-
-						sibItem["id"] = j.Id
-						if j.VlanId != nil {
-							sibItem["vlan_id"] = *j.VlanId
+					// Only the configured IP blocks are kept, blocks assigned with pnap_server_ip_block stay out of the server.
+					if ib, ok := ibcInput["ip_blocks"].([]interface{}); ok && len(ib) > 0 {
+						for _, ibItem := range ib {
+							sib := ibItem.(map[string]interface{})["server_ip_block"].([]interface{})
+							if len(sib) == 0 || sib[0] == nil {
+								continue
+							}
+							sibItem := sib[0].(map[string]interface{})
+							for _, j := range ipBlocks {
+								if sibItem["id"] == j.Id && j.VlanId != nil {
+									sibItem["vlan_id"] = *j.VlanId
+								}
+							}
 						}
-						sib[0] = sibItem
-						ibItem["server_ip_block"] = sib
-						ib[i] = ibItem
+					} else {
+						ib := make([]interface{}, len(ipBlocks))
+						for i, j := range ipBlocks {
+							ibItem := make(map[string]interface{})
+							sib := make([]interface{}, 1)
+							sibItem := make(map[string]interface{})
+
+							sibItem["id"] = j.Id
+							if j.VlanId != nil {
+								sibItem["vlan_id"] = *j.VlanId
+							}
+							sib[0] = sibItem
+							ibItem["server_ip_block"] = sib
+							ib[i] = ibItem
+						}
+						ibcInput["ip_blocks"] = ib
 					}
-					ibcInput["ip_blocks"] = ib
 				}
 			}
 			if netConf.PublicNetworkConfiguration != nil {
@@ -1746,8 +2147,16 @@ func flattenNetworkConfiguration(netConf *bmcapiclient.NetworkConfiguration, ncI
 	return ncInput
 }
 
-func flattenServerTags(tagsRead []bmcapiclient.TagAssignment, tagsInput []interface{}) []interface{} {
+// flattenServerTags fills in the configured tag assignments, tags coming from the default_tags provider block are left out.
+func flattenServerTags(tagsRead []bmcapiclient.TagAssignment, tagsInput []interface{}, defaultTags []defaultTag) []interface{} {
 	if len(tagsInput) == 0 {
+		var names []string
+		for _, l := range tagsRead {
+			names = append(names, l.Name)
+		}
+		if onlyDefaultTags(names, defaultTags) {
+			return make([]interface{}, 0)
+		}
 		tagsInput = make([]interface{}, 1)
 		tagsInputItem := make(map[string]interface{})
 		tagsInput[0] = tagsInputItem
@@ -1774,6 +2183,86 @@ func flattenServerTags(tagsRead []bmcapiclient.TagAssignment, tagsInput []interf
 	return tagsInput
 }
 
+// resourceServerCustomizeDiff rejects settings that don't fit the configured OS or pricing model at plan time,
+// instead of leaving them to the API at the end of a long apply.
+func resourceServerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	var errs []error
+	if d.NewValueKnown("os") {
+		osID := d.Get("os").(string)
+		if !strings.HasPrefix(osID, "windows/") && d.Get("rdp_allowed_ips").(*schema.Set).Len() > 0 {
+			errs = append(errs, fmt.Errorf("rdp_allowed_ips: only supported on Windows operating systems, os is %q", osID))
+		}
+		if !strings.HasPrefix(osID, "esxi/") && len(d.Get("esxi.0.datastore_configuration").([]interface{})) > 0 {
+			errs = append(errs, fmt.Errorf("esxi.0.datastore_configuration: only supported on ESXi operating systems, os is %q", osID))
+		}
+		if osID != "custom/ipxe" && len(d.Get("ipxe").([]interface{})) > 0 {
+			errs = append(errs, fmt.Errorf("ipxe: only supported with the custom/ipxe operating system, os is %q", osID))
+		}
+		if osID != "netris/softgate" && len(d.Get("netris_softgate").([]interface{})) > 0 {
+			errs = append(errs, fmt.Errorf("netris_softgate: only supported with the netris/softgate operating system, os is %q", osID))
+		}
+	}
+	// install_os_to_ram is only applied when the server is provisioned, the reset API doesn't support it.
+	if d.Id() != "" && d.HasChange("install_os_to_ram") {
+		errs = append(errs, fmt.Errorf("install_os_to_ram: can't be changed on an existing server, the API only applies it when the server is provisioned"))
+	}
+	if d.NewValueKnown("reservation_id") && len(d.Get("reservation_id").(string)) > 0 {
+		// pricing_model is unknown until the server is created when it isn't set, the API then defaults it to HOURLY
+		pricingModel := "HOURLY"
+		if v := d.Get("pricing_model").(string); d.NewValueKnown("pricing_model") && len(v) > 0 {
+			pricingModel = v
+		}
+		if pricingModel == "HOURLY" {
+			errs = append(errs, fmt.Errorf("reservation_id: requires a reservation pricing_model (e.g. ONE_MONTH_RESERVATION), pricing_model is %q", pricingModel))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateIpOrRange accepts a single IP address or a range of IP addresses in the "<first> - <last>" format.
+func validateIpOrRange(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := netip.ParseAddr(v); err == nil || isIpRange(v) {
+		return nil, nil
+	}
+	return nil, []error{fmt.Errorf("expected %s to be an IP address or a range of IP addresses (e.g. 10.0.0.1 - 10.0.0.10), got: %s", k, v)}
+}
+
+// validateIpCidrOrRange accepts a single IP address, a CIDR block or a range of IP addresses in the "<first> - <last>" format.
+func validateIpCidrOrRange(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := netip.ParsePrefix(v); err == nil {
+		return nil, nil
+	}
+	if _, err := netip.ParseAddr(v); err == nil || isIpRange(v) {
+		return nil, nil
+	}
+	return nil, []error{fmt.Errorf("expected %s to be an IP address, a CIDR block or a range of IP addresses (e.g. 10.0.0.1 - 10.0.0.10), got: %s", k, v)}
+}
+
+// isIpRange reports whether v is a range of IP addresses of the same family with the first address not after the last.
+func isIpRange(v string) bool {
+	first, last, found := strings.Cut(v, " - ")
+	if !found {
+		return false
+	}
+	firstAddr, err := netip.ParseAddr(first)
+	if err != nil {
+		return false
+	}
+	lastAddr, err := netip.ParseAddr(last)
+	if err != nil {
+		return false
+	}
+	return firstAddr.Is4() == lastAddr.Is4() && firstAddr.Compare(lastAddr) <= 0
+}
+
 func supressUserDefinedNetworkType(k, oldValue, newValue string, d *schema.ResourceData) bool {
 	if len(oldValue) > 0 && newValue == "USER_DEFINED" {
 		return true
@@ -0,0 +1,178 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func resourceServerIpBlock() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerIpBlockCreate,
+		ReadContext:   resourceServerIpBlockRead,
+		UpdateContext: resourceServerIpBlockUpdate,
+		DeleteContext: resourceServerIpBlockDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(pnapRetryTimeout),
+			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ip_block_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"vlan_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"delete_ip_block": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerMembershipImport("ip_block_id"),
+		},
+	}
+}
+
+func resourceServerIpBlockCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	ipBlockID := d.Get("ip_block_id").(string)
+
+	request := bmcapiclient.NewServerIpBlock(ipBlockID)
+	if v, ok := d.GetOk("vlan_id"); ok {
+		vlanID := int32(v.(int))
+		request.VlanId = &vlanID
+	}
+
+	requestCommand := server.NewAddServerIpBlockCommand(client, serverID, *request)
+	_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(serverID + "/" + ipBlockID)
+
+	waitResultError := ipBlockWaitForAssign(ctx, ipBlockID, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
+	if waitResultError != nil {
+		return diag.FromErr(waitResultError)
+	}
+
+	return resourceServerIpBlockRead(ctx, d, m)
+}
+
+func resourceServerIpBlockRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	ipBlockID := d.Get("ip_block_id").(string)
+
+	requestCommand := server.NewGetServerCommand(client, serverID)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Server (%s) not found, removing IP block assignment from state", serverID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	var ipBlock *bmcapiclient.ServerIpBlock
+	if resp.NetworkConfiguration.IpBlocksConfiguration != nil {
+		for _, v := range resp.NetworkConfiguration.IpBlocksConfiguration.IpBlocks {
+			if v.Id == ipBlockID {
+				ipBlock = &v
+				break
+			}
+		}
+	}
+	if ipBlock == nil {
+		if !d.IsNewResource() {
+			log.Printf("[WARN] IP block (%s) is not assigned to server (%s), removing from state", ipBlockID, serverID)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("IP block %s is not assigned to server %s", ipBlockID, serverID)
+	}
+
+	if ipBlock.VlanId != nil {
+		d.Set("vlan_id", int(*ipBlock.VlanId))
+	}
+	return nil
+}
+
+func resourceServerIpBlockUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// delete_ip_block is only used when the IP block is removed from the server
+	return resourceServerIpBlockRead(ctx, d, m)
+}
+
+func resourceServerIpBlockDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	ipBlockID := d.Get("ip_block_id").(string)
+
+	var deleteIpBlock = d.Get("delete_ip_block").(bool)
+	relinquishIpBlock := bmcapiclient.RelinquishIpBlock{}
+	relinquishIpBlock.DeleteIpBlocks = &deleteIpBlock
+
+	requestCommand := server.NewRemoveServerIpBlockCommand(client, serverID, ipBlockID, relinquishIpBlock)
+	_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	// a relinquished IP block is deleted along with the assignment, there is nothing left to wait for
+	if !deleteIpBlock {
+		waitResultError := ipBlockWaitForUnassign(ctx, ipBlockID, m.(*providerMeta), d.Timeout(schema.TimeoutDelete))
+		if waitResultError != nil {
+			return diag.FromErr(waitResultError)
+		}
+	}
+
+	return nil
+}
+
+func ipBlockWaitForAssign(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
+	log.Printf("Waiting for ip block %s to be assigned...", id)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"assigning"},
+		Target:       []string{"assigned"},
+		Refresh:      refreshForIpBlockStatus(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapIpBlockRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error waiting for ip block (%s) to be assigned: %v", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapServerIpBlock_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server_ip_block." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server_ip_block"),
+		Steps: []resource.TestStep{
+			{
+				// the IP block settles from assigning to assigned
+				Config: testUnitProviderConfig(api) + testUnitServerIpBlockResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(rLine, "ip_block_id", "pnap_ip_block."+rName, "id"),
+					resource.TestCheckResourceAttrSet(rLine, "vlan_id"),
+				),
+			},
+			{
+				ResourceName:            rLine,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"delete_ip_block"},
+			},
+		},
+	})
+}
+
+func TestUnitPnapServerIpBlock_configuredServerIpBlocks(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	config := testUnitProviderConfig(api) + fmt.Sprintf(`
+resource "pnap_ip_block" "%s-server" {
+	location = "PHX"
+	cidr_block_size = "/29"
+}
+
+resource "pnap_ip_block" "%s" {
+	location = "PHX"
+	cidr_block_size = "/29"
+}
+
+resource "pnap_server" "%s" {
+	hostname = "%s"
+	os = "ubuntu/jammy"
+	type = "s1.c1.medium"
+	location = "PHX"
+	network_configuration {
+		ip_blocks_configuration {
+			configuration_type = "USER_DEFINED"
+			ip_blocks {
+				server_ip_block {
+					id = pnap_ip_block.%s-server.id
+				}
+			}
+		}
+	}
+}
+
+resource "pnap_server_ip_block" "%s" {
+	server_id = pnap_server.%s.id
+	ip_block_id = pnap_ip_block.%s.id
+}`, rName, rName, rName, rName, rName, rName, rName, rName)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server_ip_block"),
+		Steps: []resource.TestStep{
+			{
+				// the block assigned with pnap_server_ip_block stays out of the IP blocks of the server
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pnap_server."+rName, "network_configuration.0.ip_blocks_configuration.0.ip_blocks.#", "1"),
+					resource.TestCheckResourceAttrPair("pnap_server."+rName, "network_configuration.0.ip_blocks_configuration.0.ip_blocks.0.server_ip_block.0.id",
+						"pnap_ip_block."+rName+"-server", "id"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testUnitServerIpBlockResource(rName string) string {
+	return testAccCreateServerResource(rName) + fmt.Sprintf(`
+resource "pnap_ip_block" "%s" {
+	location = "PHX"
+	cidr_block_size = "/29"
+}
+
+resource "pnap_server_ip_block" "%s" {
+	server_id = pnap_server.%s.id
+	ip_block_id = pnap_ip_block.%s.id
+}`, rName, rName, rName, rName)
+}
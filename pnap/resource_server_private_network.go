@@ -0,0 +1,275 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func resourceServerPrivateNetwork() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerPrivateNetworkCreate,
+		ReadContext:   resourceServerPrivateNetworkRead,
+		DeleteContext: resourceServerPrivateNetworkDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(pnapRetryTimeout),
+			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"private_network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ips": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIpOrRange,
+				},
+			},
+			"dhcp": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"status_description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vlan_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerMembershipImport("private_network_id"),
+		},
+	}
+}
+
+func resourceServerPrivateNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("private_network_id").(string)
+
+	request := bmcapiclient.NewServerPrivateNetwork(networkID)
+	if v, ok := d.GetOk("ips"); ok {
+		ips := v.(*schema.Set).List()
+		request.Ips = make([]string, len(ips))
+		for i, j := range ips {
+			request.Ips[i] = j.(string)
+		}
+	}
+	if v, ok := d.GetOkExists("dhcp"); ok {
+		dhcp := v.(bool)
+		request.Dhcp = &dhcp
+	}
+
+	requestCommand := server.NewAddServerPrivateNetworkCommand(client, serverID, *request)
+	_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(serverID + "/" + networkID)
+
+	waitResultError := serverNetworkWaitForAssign(ctx, serverID, networkID, false, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
+	if waitResultError != nil {
+		return diag.FromErr(waitResultError)
+	}
+
+	return resourceServerPrivateNetworkRead(ctx, d, m)
+}
+
+func resourceServerPrivateNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("private_network_id").(string)
+
+	requestCommand := server.NewGetServerCommand(client, serverID)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Server (%s) not found, removing private network membership from state", serverID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	var privateNetwork *bmcapiclient.ServerPrivateNetwork
+	if resp.NetworkConfiguration.PrivateNetworkConfiguration != nil {
+		for _, v := range resp.NetworkConfiguration.PrivateNetworkConfiguration.PrivateNetworks {
+			if v.Id == networkID {
+				privateNetwork = &v
+				break
+			}
+		}
+	}
+	if privateNetwork == nil {
+		if !d.IsNewResource() {
+			log.Printf("[WARN] Server (%s) is not a member of private network (%s), removing from state", serverID, networkID)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("server %s is not a member of private network %s", serverID, networkID)
+	}
+
+	var ips []interface{}
+	for _, v := range privateNetwork.Ips {
+		ips = append(ips, v)
+	}
+	d.Set("ips", ips)
+	if privateNetwork.Dhcp != nil {
+		d.Set("dhcp", *privateNetwork.Dhcp)
+	}
+	if privateNetwork.StatusDescription != nil {
+		d.Set("status_description", *privateNetwork.StatusDescription)
+	} else {
+		d.Set("status_description", "")
+	}
+	if privateNetwork.VlanId != nil {
+		d.Set("vlan_id", int(*privateNetwork.VlanId))
+	}
+	return nil
+}
+
+func resourceServerPrivateNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("private_network_id").(string)
+
+	requestCommand := server.NewRemoveServerPrivateNetworkCommand(client, serverID, networkID)
+	_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	waitResultError := serverNetworkWaitForRemove(ctx, serverID, networkID, false, m.(*providerMeta), d.Timeout(schema.TimeoutDelete))
+	if waitResultError != nil {
+		return diag.FromErr(waitResultError)
+	}
+
+	return nil
+}
+
+// resourceServerMembershipImport accepts IDs in the <server_id>/<member_id> format, the member ID is stored in the given attribute
+func resourceServerMembershipImport(memberAttribute string) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		parts := strings.SplitN(d.Id(), "/", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("unexpected format of ID (%s), expected <server_id>/<%s>", d.Id(), memberAttribute)
+		}
+		d.Set("server_id", parts[0])
+		d.Set(memberAttribute, parts[1])
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+func serverNetworkWaitForAssign(ctx context.Context, serverID, networkID string, public bool, meta *providerMeta, timeout time.Duration) error {
+	log.Printf("Waiting for server %s to be added to network %s...", serverID, networkID)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"in-progress"},
+		Target:       []string{"assigned"},
+		Refresh:      refreshForServerNetworkStatus(ctx, meta, serverID, networkID, public),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error waiting for server (%s) to be added to network (%s): %v", serverID, networkID, err)
+	}
+
+	return nil
+}
+
+func serverNetworkWaitForRemove(ctx context.Context, serverID, networkID string, public bool, meta *providerMeta, timeout time.Duration) error {
+	log.Printf("Waiting for server %s to be removed from network %s...", serverID, networkID)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"in-progress", "assigned"},
+		Target:       []string{"removed"},
+		Refresh:      refreshForServerNetworkStatus(ctx, meta, serverID, networkID, public),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error waiting for server (%s) to be removed from network (%s): %v", serverID, networkID, err)
+	}
+
+	return nil
+}
+
+// refreshForServerNetworkStatus reports the status of the server's membership in a private or public network,
+// a membership the server no longer has (or a server that is gone) is reported as removed.
+func refreshForServerNetworkStatus(ctx context.Context, meta *providerMeta, serverID, networkID string, public bool) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+
+		requestCommand := server.NewGetServerCommand(meta.client, serverID)
+
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
+		if err != nil {
+			if isNotFound(err) {
+				return 0, "removed", nil
+			}
+			return 0, "", err
+		}
+		netConf := resp.NetworkConfiguration
+		if public && netConf.PublicNetworkConfiguration != nil {
+			for _, v := range netConf.PublicNetworkConfiguration.PublicNetworks {
+				if v.Id == networkID {
+					return 0, serverNetworkStatus(v.StatusDescription), nil
+				}
+			}
+		}
+		if !public && netConf.PrivateNetworkConfiguration != nil {
+			for _, v := range netConf.PrivateNetworkConfiguration.PrivateNetworks {
+				if v.Id == networkID {
+					return 0, serverNetworkStatus(v.StatusDescription), nil
+				}
+			}
+		}
+		return 0, "removed", nil
+	}
+}
+
+// serverNetworkStatus treats a membership without a status as assigned.
+func serverNetworkStatus(statusDescription *string) string {
+	if statusDescription == nil || len(*statusDescription) == 0 {
+		return "assigned"
+	}
+	return *statusDescription
+}
@@ -0,0 +1,45 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapServerPrivateNetwork_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server_private_network." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server_private_network"),
+		Steps: []resource.TestStep{
+			{
+				// the membership settles from in-progress to assigned
+				Config: testUnitProviderConfig(api) + testUnitServerPrivateNetworkResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status_description", "assigned"),
+					resource.TestCheckResourceAttr(rLine, "ips.#", "1"),
+					resource.TestCheckResourceAttrSet(rLine, "vlan_id"),
+					resource.TestCheckResourceAttrPair(rLine, "server_id", "pnap_server."+rName, "id"),
+				),
+			},
+			{
+				ResourceName:      rLine,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testUnitServerPrivateNetworkResource(rName string) string {
+	return testAccCreateServerResource(rName) + testAccCreatePrivateNetworkResource_basic(rName) + fmt.Sprintf(`
+resource "pnap_server_private_network" "%s" {
+	server_id = pnap_server.%s.id
+	private_network_id = pnap_private_network.%s.id
+	ips = ["10.0.1.1"]
+}`, rName, rName, rName)
+}
@@ -0,0 +1,166 @@
+package pnap
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func resourceServerPublicNetwork() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceServerPublicNetworkCreate,
+		ReadContext:   resourceServerPublicNetworkRead,
+		DeleteContext: resourceServerPublicNetworkDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(pnapRetryTimeout),
+			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"public_network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ips": {
+				Type:     schema.TypeSet,
+				Required: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIpOrRange,
+				},
+			},
+			"compute_slaac_ip": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			"status_description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vlan_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerMembershipImport("public_network_id"),
+		},
+	}
+}
+
+func resourceServerPublicNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("public_network_id").(string)
+
+	ips := d.Get("ips").(*schema.Set).List()
+	request := bmcapiclient.NewServerPublicNetwork(networkID, make([]string, len(ips)))
+	for i, j := range ips {
+		request.Ips[i] = j.(string)
+	}
+	if d.Get("compute_slaac_ip").(bool) {
+		computeSlaacIp := true
+		request.ComputeSlaacIp = &computeSlaacIp
+	}
+
+	requestCommand := server.NewAddServerPublicNetworkCommand(client, serverID, *request)
+	_, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(serverID + "/" + networkID)
+
+	waitResultError := serverNetworkWaitForAssign(ctx, serverID, networkID, true, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
+	if waitResultError != nil {
+		return diag.FromErr(waitResultError)
+	}
+
+	return resourceServerPublicNetworkRead(ctx, d, m)
+}
+
+func resourceServerPublicNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("public_network_id").(string)
+
+	requestCommand := server.NewGetServerCommand(client, serverID)
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Server (%s) not found, removing public network membership from state", serverID)
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	var publicNetwork *bmcapiclient.ServerPublicNetwork
+	if resp.NetworkConfiguration.PublicNetworkConfiguration != nil {
+		for _, v := range resp.NetworkConfiguration.PublicNetworkConfiguration.PublicNetworks {
+			if v.Id == networkID {
+				publicNetwork = &v
+				break
+			}
+		}
+	}
+	if publicNetwork == nil {
+		if !d.IsNewResource() {
+			log.Printf("[WARN] Server (%s) is not a member of public network (%s), removing from state", serverID, networkID)
+			d.SetId("")
+			return nil
+		}
+		return diag.Errorf("server %s is not a member of public network %s", serverID, networkID)
+	}
+
+	var ips []interface{}
+	for _, v := range publicNetwork.Ips {
+		ips = append(ips, v)
+	}
+	d.Set("ips", ips)
+	if publicNetwork.StatusDescription != nil {
+		d.Set("status_description", *publicNetwork.StatusDescription)
+	} else {
+		d.Set("status_description", "")
+	}
+	if publicNetwork.VlanId != nil {
+		d.Set("vlan_id", int(*publicNetwork.VlanId))
+	}
+	return nil
+}
+
+func resourceServerPublicNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	serverID := d.Get("server_id").(string)
+	networkID := d.Get("public_network_id").(string)
+
+	requestCommand := server.NewRemoveServerPublicNetworkCommand(client, serverID, networkID)
+	_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	waitResultError := serverNetworkWaitForRemove(ctx, serverID, networkID, true, m.(*providerMeta), d.Timeout(schema.TimeoutDelete))
+	if waitResultError != nil {
+		return diag.FromErr(waitResultError)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapServerPublicNetwork_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server_public_network." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server_public_network"),
+		Steps: []resource.TestStep{
+			{
+				// the membership settles from in-progress to assigned
+				Config: testUnitProviderConfig(api) + testUnitServerPublicNetworkResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status_description", "assigned"),
+					resource.TestCheckResourceAttr(rLine, "ips.#", "1"),
+					resource.TestCheckResourceAttrSet(rLine, "vlan_id"),
+					resource.TestCheckResourceAttrPair(rLine, "public_network_id", "pnap_public_network."+rName, "id"),
+				),
+			},
+			{
+				ResourceName:            rLine,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"compute_slaac_ip"},
+			},
+		},
+	})
+}
+
+func testUnitServerPublicNetworkResource(rName string) string {
+	return testAccCreateServerResource(rName) + testUnitPublicNetworkWithIpBlockResource(rName) + fmt.Sprintf(`
+resource "pnap_server_public_network" "%s" {
+	server_id = pnap_server.%s.id
+	public_network_id = pnap_public_network.%s.id
+	ips = [cidrhost(pnap_ip_block.%s.cidr, 2)]
+}`, rName, rName, rName, rName)
+}
@@ -1,16 +1,20 @@
 package pnap
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
 	helperserver "github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/server"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
 )
 
@@ -97,6 +101,31 @@ func TestAccPnapServer_basic(t *testing.T) {
 	})
 }
 
+func TestAccPnapServer_disappears(t *testing.T) {
+
+	var server bmcapiclient.Server
+	rNameSuffix := acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rName := "acctest-" + rNameSuffix
+	rLine := "pnap_server." + rName
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckServerResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				// create the server and delete it behind Terraform's back
+				Config: testAccCreateServerResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckServerExists(rLine, &server),
+					testAccCheckServerDisappears(rLine),
+				),
+				// the refresh drops the server from state, so the plan proposes recreation
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
 func TestAccPnapServer_shutdowntest(t *testing.T) {
 	rNameSuffix := acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
 	rName := "acctest-" + rNameSuffix
@@ -143,10 +172,464 @@ func TestAccPnapServer_shutdowntest(t *testing.T) {
 	})
 }
 
+func TestUnitPnapServer_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server"),
+		Steps: []resource.TestStep{
+			{
+				// the server settles from creating to powered-on
+				Config: testUnitProviderConfig(api) + testAccCreateServerResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "hostname", rName),
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+					resource.TestCheckResourceAttr(rLine, "pricing_model", "HOURLY"),
+					resource.TestCheckResourceAttrSet(rLine, "private_ip_addresses.#"),
+					resource.TestCheckResourceAttrSet(rLine, "public_ip_addresses.#"),
+					resource.TestCheckResourceAttrSet(rLine, "provisioned_on"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testAccPowerOffServerResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status", "powered-off"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testUnitProviderConfig(api) + testAccPowerOnServerResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// the server goes through rebooting before it is powered on again
+				Config: testUnitProviderConfig(api) + testAccRebootServerResource(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestUnitPnapServer_powerState(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server." + rName
+	var serverID string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, `power_state = "powered-off"`),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckServerID(rLine, &serverID, false),
+					resource.TestCheckResourceAttr(rLine, "status", "powered-off"),
+					resource.TestCheckResourceAttr(rLine, "power_state", "powered-off"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, `power_state = "powered-on"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+					testUnitCheckServerActions(api, &serverID, "shutdown", "power-on"),
+				),
+			},
+			{
+				// the server is powered off outside of Terraform and powered on again
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.servers[serverID].Status = "powered-off"
+				},
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, `power_state = "powered-on"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+					testUnitCheckServerActions(api, &serverID, "shutdown", "power-on", "power-on"),
+				),
+			},
+			{
+				// the server is rebooted only when the trigger changes
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, "power_state = \"powered-on\"\n reboot_trigger = \"1\""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+					testUnitCheckServerActions(api, &serverID, "shutdown", "power-on", "power-on", "reboot"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPnapServer_reinstallTrigger(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server." + rName
+	var serverID string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server"),
+		Steps: []resource.TestStep{
+			{
+				// setting the trigger on a new server doesn't reinstall it
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, `reinstall_trigger = "1"`),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckServerID(rLine, &serverID, false),
+					testUnitCheckServerActions(api, &serverID),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, `reinstall_trigger = "2"`),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckServerID(rLine, &serverID, false),
+					testUnitCheckServerActions(api, &serverID, "reset"),
+					resource.TestCheckResourceAttr(rLine, "status", "powered-on"),
+					resource.TestCheckResourceAttr(rLine, "password", "mock-reset-password"),
+				),
+			},
+			{
+				Config:      testUnitProviderConfig(api) + testUnitServerResourceWith(rName, "reinstall_trigger = \"2\"\n install_os_to_ram = true"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`install_os_to_ram: can't be changed on an existing server`),
+			},
+		},
+	})
+}
+
+func TestUnitPnapServer_failedUpdate(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server." + rName
+	var serverID string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, `reinstall_trigger = "1"`),
+				Check:  testUnitCheckServerID(rLine, &serverID, false),
+			},
+			{
+				// the reinstall goes through, the following description update fails
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.failures = []mockFailure{{method: http.MethodPatch, status: http.StatusInternalServerError}}
+				},
+				Config:      testUnitProviderConfig(api) + testUnitServerResourceWith(rName, "reinstall_trigger = \"2\"\n description = \"updated\""),
+				ExpectError: regexp.MustCompile("500"),
+			},
+			{
+				// the description wasn't recorded
+				Config:             testUnitProviderConfig(api) + testUnitServerResourceWith(rName, "reinstall_trigger = \"2\"\n description = \"updated\""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// the reinstall was recorded, it isn't repeated
+				Config:   testUnitProviderConfig(api) + testUnitServerResourceWith(rName, `reinstall_trigger = "2"`),
+				PlanOnly: true,
+			},
+			{
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, `reinstall_trigger = "2"`),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckServerActions(api, &serverID, "reset"),
+					resource.TestCheckResourceAttr(rLine, "password", "mock-reset-password"),
+				),
+			},
+		},
+	})
+}
+
+func TestExpandServerReset(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceServer().Schema, map[string]interface{}{
+		"hostname":                      "reinstall",
+		"os":                            "proxmox/bookworm",
+		"type":                          "s1.c1.medium",
+		"location":                      "PHX",
+		"ssh_keys":                      []interface{}{"ssh-ed25519 AAAA"},
+		"management_access_allowed_ips": []interface{}{"10.0.0.0/24"},
+		"install_os_to_ram":             true,
+		"cloud_init":                    []interface{}{map[string]interface{}{"user_data": "I2Nsb3VkLWNvbmZpZw=="}},
+	})
+	request := expandServerReset(d)
+	if !reflect.DeepEqual(request.SshKeys, []string{"ssh-ed25519 AAAA"}) {
+		t.Errorf("unexpected ssh keys %v", request.SshKeys)
+	}
+	if request.OsConfiguration == nil || request.OsConfiguration.Proxmox == nil {
+		t.Fatalf("expected a Proxmox OS configuration, got %+v", request.OsConfiguration)
+	}
+	if !reflect.DeepEqual(request.OsConfiguration.Proxmox.ManagementAccessAllowedIps, []string{"10.0.0.0/24"}) {
+		t.Errorf("unexpected management access allowed IPs %v", request.OsConfiguration.Proxmox.ManagementAccessAllowedIps)
+	}
+	if request.OsConfiguration.Esxi != nil || request.OsConfiguration.Windows != nil {
+		t.Errorf("unexpected ESXi or Windows OS configuration %+v", request.OsConfiguration)
+	}
+	// the reset API doesn't support cloud-init nor RAM installs, they aren't sent
+	if len(request.OsConfiguration.AdditionalProperties) > 0 || len(request.AdditionalProperties) > 0 {
+		t.Errorf("unexpected additional properties %v %v", request.OsConfiguration.AdditionalProperties, request.AdditionalProperties)
+	}
+}
+
+func TestUnitPnapServer_checkAvailability(t *testing.T) {
+	api := newMockAPI(t)
+	api.productStock["s1.c1.medium"] = map[string]float32{"PHX": 0, "ASH": 3}
+	api.quotas[serverQuotaID] = &bmcapiclient.Quota{Id: serverQuotaID, Name: "Servers", Limit: 5, Used: 4, Unit: "COUNT"}
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	checkAvailability := testUnitProviderConfigWith(api, "\tcheck_availability = true\n")
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      checkAvailability + testUnitServerResourceWith(rName, ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta("1 s1.c1.medium server(s) planned in PHX but only 0 available, available in ASH (3)")),
+			},
+			{
+				// the check is opt-in
+				Config:             testUnitProviderConfig(api) + testUnitServerResourceWith(rName, ""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.productStock["s1.c1.medium"]["PHX"] = 2
+				},
+				Config:             checkAvailability + testUnitServerResourceWith(rName, ""),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config:      checkAvailability + testUnitServerResourceWith(rName, "") + testUnitServerResourceWith(rName+"-2", ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta("2 new server(s) planned but the Servers quota has 4 of 5 in use")),
+			},
+			{
+				// every instance counts, even if they share a hostname
+				Config:      checkAvailability + testUnitServerResourceWith(rName, "count = 3"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(regexp.QuoteMeta("3 s1.c1.medium server(s) planned in PHX but only 2 available")),
+			},
+			{
+				// the warn mode doesn't fail the plan
+				Config:             testUnitProviderConfigWith(api, "\tcheck_availability = true\n\tavailability_check_mode = \"warn\"\n") + testUnitServerResourceWith(rName, "count = 3"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestResourceServerAvailabilityDiff_applyWalk(t *testing.T) {
+	api := newMockAPI(t)
+	api.productStock["s1.c1.medium"] = map[string]float32{"PHX": 2}
+	api.quotas[serverQuotaID] = &bmcapiclient.Quota{Id: serverQuotaID, Name: "Servers", Limit: 5, Used: 3, Unit: "COUNT"}
+	// each walk configures the provider anew and plans both servers, calling created after a server is planned
+	walk := func(created func()) error {
+		meta := testUnitConfigureProvider(t, api, map[string]interface{}{"check_availability": true})
+		for i := 1; i <= 2; i++ {
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"hostname": fmt.Sprintf("server-%d", i),
+				"os":       "ubuntu/jammy",
+				"type":     "s1.c1.medium",
+				"location": "PHX",
+			})
+			if _, err := resourceServer().Diff(context.Background(), nil, config, meta); err != nil {
+				return err
+			}
+			created()
+		}
+		return nil
+	}
+
+	if err := walk(func() {}); err != nil {
+		t.Fatalf("planning 2 servers with a stock of 2 failed: %v", err)
+	}
+	// the apply walk plans each server again, after the servers before it took up stock and quota
+	err := walk(func() {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		api.productStock["s1.c1.medium"]["PHX"]--
+		api.quotas[serverQuotaID].Used++
+	})
+	if err != nil {
+		t.Errorf("the apply walk reported a shortfall for the servers it created: %v", err)
+	}
+}
+
+func TestUnitPnapServer_planValidation(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	invalid := []struct {
+		attributes    string
+		expectedError string
+	}{
+		{`rdp_allowed_ips = ["10.0.0.0/24"]`, `rdp_allowed_ips: only supported on Windows`},
+		{"esxi {\n datastore_configuration {\n datastore_name = \"ds\"\n }\n }", `esxi.0.datastore_configuration: only supported on ESXi`},
+		{`ipxe { url = "https://example.com/boot.ipxe" }`, `ipxe: only supported with the custom/ipxe`},
+		{`netris_softgate { controller_address = "10.0.0.1" }`, `netris_softgate: only supported with the netris/softgate`},
+		// pricing_model is omitted, so it defaults to HOURLY
+		{`reservation_id = "3bc6a6f3-0a0a-4f9b-9d2c-6d6b1c2f5e1a"`, `reservation_id: requires a reservation pricing_model (e.g. ONE_MONTH_RESERVATION), pricing_model is "HOURLY"`},
+		{"reservation_id = \"3bc6a6f3-0a0a-4f9b-9d2c-6d6b1c2f5e1a\"\n pricing_model = \"HOURLY\"", `reservation_id: requires a reservation pricing_model`},
+		{"storage_configuration {\n root_partition {\n raid = \"RAID_5\"\n }\n }", `expected storage_configuration.0.root_partition.0.raid to be`},
+		{`management_access_allowed_ips = ["10.0.0.300"]`, `to be an IP address, a CIDR block or a range of IP addresses`},
+	}
+	var steps []resource.TestStep
+	for _, v := range invalid {
+		steps = append(steps, resource.TestStep{
+			Config:      testUnitProviderConfig(api) + testUnitServerResourceWith(rName, v.attributes),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile(regexp.QuoteMeta(v.expectedError)),
+		})
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps:             steps,
+	})
+}
+
+func TestUnitPnapServer_osChange(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server." + rName
+	var serverID string
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitServerResourceOs(rName, "ubuntu/jammy", ""),
+				Check:  testUnitCheckServerID(rLine, &serverID, false),
+			},
+			{
+				// the reset API can't change the OS, a new server is provisioned
+				Config: testUnitProviderConfig(api) + testUnitServerResourceOs(rName, "ubuntu/noble", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckServerID(rLine, &serverID, true),
+					resource.TestCheckResourceAttr(rLine, "os", "ubuntu/noble"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPnapServer_storageConfiguration(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_server." + rName
+	var serverID string
+	storage := "storage_configuration {\n root_partition {\n raid = \"NO_RAID\"\n }\n }"
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_server"),
+		Steps: []resource.TestStep{
+			{
+				// the API reports the size of the partition it created, the configured default is kept
+				Config: testUnitProviderConfig(api) + testUnitServerResourceWith(rName, storage),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckServerID(rLine, &serverID, false),
+					resource.TestCheckResourceAttr(rLine, "storage_configuration.0.root_partition.0.size", "-1"),
+					testUnitCheckServerRootPartitionSize(api, &serverID, 893),
+				),
+			},
+			{
+				Config:   testUnitProviderConfig(api) + testUnitServerResourceWith(rName, storage),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testUnitCheckServerRootPartitionSize verifies the size of the root partition reported by the fake API.
+func testUnitCheckServerRootPartitionSize(m *mockAPI, id *string, expected int32) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		rp := m.servers[*id].StorageConfiguration.RootPartition
+		if rp == nil || rp.Size == nil || *rp.Size != expected {
+			return fmt.Errorf("server %s has root partition %v, expected a size of %d", *id, rp, expected)
+		}
+		return nil
+	}
+}
+
+func TestValidateIpCidrOrRange(t *testing.T) {
+	for v, valid := range map[string]bool{
+		"10.0.0.1":               true,
+		"10.0.0.0/24":            true,
+		"2001:db8::/64":          true,
+		"10.0.0.1 - 10.0.0.10":   true,
+		"10.0.0.10 - 10.0.0.1":   false,
+		"10.0.0.1 - 2001:db8::1": false,
+		"10.0.0.1-10.0.0.10":     false,
+		"10.0.0.256":             false,
+		"server.example.com":     false,
+	} {
+		_, errs := validateIpCidrOrRange(v, "rdp_allowed_ips")
+		if valid != (len(errs) == 0) {
+			t.Errorf("validateIpCidrOrRange(%q) returned %v, expected valid: %t", v, errs, valid)
+		}
+	}
+	if _, errs := validateIpOrRange("10.0.0.0/24", "ips"); len(errs) == 0 {
+		t.Errorf("validateIpOrRange accepted a CIDR block")
+	}
+}
+
+func testUnitServerResourceWith(rName, attributes string) string {
+	return testUnitServerResourceOs(rName, "ubuntu/jammy", attributes)
+}
+
+func testUnitServerResourceOs(rName, osID, attributes string) string {
+	return fmt.Sprintf(`
+resource "pnap_server" "%s" {
+	hostname = "%s"
+	os = "%s"
+	type = "s1.c1.medium"
+	location = "PHX"
+	%s
+}`, rName, rName, osID, attributes)
+}
+
+// testUnitCheckServerActions verifies the actions requested for the server in the fake API.
+func testUnitCheckServerActions(m *mockAPI, id *string, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if actual := m.serverActions[*id]; !reflect.DeepEqual(actual, expected) {
+			return fmt.Errorf("server %s has actions %v, expected %v", *id, actual, expected)
+		}
+		return nil
+	}
+}
+
+// testUnitCheckServerID records the ID of the server on the first call and verifies
+// on later calls whether the server was replaced since.
+func testUnitCheckServerID(resourceName string, id *string, replaced bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+		if *id != "" && replaced == (rs.Primary.ID == *id) {
+			return fmt.Errorf("server %s has ID %s, expected replaced: %t", *id, rs.Primary.ID, replaced)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
 // testAccPreCheck validates the necessary test API keys exist
 // in the testing environment
 func testAccPreCheck(t *testing.T) {
-	//client := testAccProvider.Meta().(receiver.BMCSDK)
+	//client := testAccProvider.Meta().(*providerMeta).client
 	/* err := client..VerifyConfiguration()
 	if err != nil {
 		t.Fatal(err)
@@ -157,7 +640,7 @@ func testAccPreCheck(t *testing.T) {
 // has been destroyed
 func testAccCheckServerResourceDestroy(s *terraform.State) error {
 	// get configured client from metadata
-	client := testAccProvider.Meta().(receiver.BMCSDK)
+	client := testAccProvider.Meta().(*providerMeta).client
 	// loop through the resources in state, verifying each server
 	// is destroyed
 	for _, rs := range s.RootModule().Resources {
@@ -293,7 +776,7 @@ func testAccCheckServerExists(resourceName string, server *bmcapiclient.Server)
 		}
 
 		// retrieve the configured client from the test setup
-		client := testAccProvider.Meta().(receiver.BMCSDK)
+		client := testAccProvider.Meta().(*providerMeta).client
 
 		requestCommand := helperserver.NewGetServerCommand(client, rs.Primary.ID)
 
@@ -370,13 +853,31 @@ func testAccCheckServerStatusAttribute(resourceName string, server *bmcapiclient
 		return nil
 	}
 }
+
+// testAccCheckServerDisappears deletes the server outside of Terraform
+func testAccCheckServerDisappears(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		client := testAccProvider.Meta().(*providerMeta).client
+
+		requestCommand := helperserver.NewDeleteServerCommand(client, rs.Primary.ID)
+		_, err := requestCommand.Execute()
+
+		return err
+	}
+}
+
 func init() {
 	resource.AddTestSweepers("server", &resource.Sweeper{
 		Name: "server",
 		F: func(region string) error {
 
 			// retrieve the configured client from the test setup
-			client := testAccProvider.Meta().(receiver.BMCSDK)
+			client := testAccProvider.Meta().(*providerMeta).client
 
 			requestCommand := helperserver.NewGetServersCommand(client)
 			resp, err := requestCommand.Execute()
@@ -1,10 +1,11 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
+	"log"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/bmcapi/sshkey"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
@@ -12,10 +13,10 @@ import (
 
 func resourceSshKey() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSshKeyCreate,
-		Read:   resourceSshKeyRead,
-		Update: resourceSshKeyUpdate,
-		Delete: resourceSshKeyDelete,
+		CreateContext: resourceSshKeyCreate,
+		ReadContext: resourceSshKeyRead,
+		UpdateContext: resourceSshKeyUpdate,
+		DeleteContext: resourceSshKeyDelete,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -55,9 +56,9 @@ func resourceSshKey() *schema.Resource {
 	}
 }
 
-func resourceSshKeyCreate(d *schema.ResourceData, m interface{}) error {
+func resourceSshKeyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &bmcapiclient.SshKeyCreate{}
 	request.Name = d.Get("name").(string)
@@ -66,9 +67,9 @@ func resourceSshKeyCreate(d *schema.ResourceData, m interface{}) error {
 
 	requestCommand := sshkey.NewCreateSshKeyCommand(client, *request)
 
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	//code := resp.StatusCode
 	//if code == 201 {
@@ -79,25 +80,30 @@ func resourceSshKeyCreate(d *schema.ResourceData, m interface{}) error {
 	/* } else {
 		response := &dto.ErrorMessage{}
 		response.FromBytes(resp)
-		return fmt.Errorf("API Returned Code %v Message: %s Validation Errors: %s", code, response.Message, response.ValidationErrors)
+		return diag.Errorf("API Returned Code %v Message: %s Validation Errors: %s", code, response.Message, response.ValidationErrors)
 	} */
 
-	return resourceSshKeyRead(d, m)
+	return resourceSshKeyRead(ctx, d, m)
 }
 
-func resourceSshKeyRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceSshKeyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	keyID := d.Id()
 	requestCommand := sshkey.NewGetSshKeyCommand(client, keyID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] SSH key (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	/* code := resp.StatusCode
 	if code != 200 {
 		response := &dto.ErrorMessage{}
 		response.FromBytes(resp)
-		return fmt.Errorf("API Returned Code from read method: %v, Message: %v, Validation Errors: %v", code, response.Message, response.ValidationErrors)
+		return diag.Errorf("API Returned Code from read method: %v, Message: %v, Validation Errors: %v", code, response.Message, response.ValidationErrors)
 	} */
 	//response := &dto.SshKey{}
 	//response.FromBytes(resp)
@@ -112,9 +118,9 @@ func resourceSshKeyRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceSshKeyUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceSshKeyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if d.HasChange("name") || d.HasChange("default") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		//var requestCommand command.Executor
 
 		request := &bmcapiclient.SshKeyUpdate{}
@@ -123,39 +129,39 @@ func resourceSshKeyUpdate(d *schema.ResourceData, m interface{}) error {
 		//request.Id = d.Id()
 		requestCommand := sshkey.NewUpdateSshKeyCommand(client, d.Id(), *request)
 
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		/* code := resp.StatusCode
 		if code != 200 {
 			response := &dto.ErrorMessage{}
 			response.FromBytes(resp)
-			return fmt.Errorf("API Returned Code %v Message: %s Validation Errors: %s", code, response.Message, response.ValidationErrors)
+			return diag.Errorf("API Returned Code %v Message: %s Validation Errors: %s", code, response.Message, response.ValidationErrors)
 
 		} */
 	} else {
-		return fmt.Errorf("unsuported action")
+		return diag.Errorf("unsuported action")
 	}
-	return resourceSshKeyRead(d, m)
+	return resourceSshKeyRead(ctx, d, m)
 
 }
 
-func resourceSshKeyDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceSshKeyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	sshKeyID := d.Id()
 
 	requestCommand := sshkey.NewDeleteSshKeyCommand(client, sshKeyID)
-	_, err := requestCommand.Execute()
+	_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	/* code := resp.StatusCode
 	if code != 200 && code != 404 {
 		response := &dto.ErrorMessage{}
 		response.FromBytes(resp)
-		return fmt.Errorf("API Returned Code: %v, Message: %v, Validation Errors: %v", code, response.Message, response.ValidationErrors)
+		return diag.Errorf("API Returned Code: %v, Message: %v, Validation Errors: %v", code, response.Message, response.ValidationErrors)
 	} */
 	return nil
 }
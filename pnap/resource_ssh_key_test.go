@@ -0,0 +1,49 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testUnitSshKey = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQDF9LdAFElNCi7JoWh6KUcchrJ2Gac1aqGRPpdZNowObpRtmiRCecAMb7bUgNAaNfcmwiQi7tos9TlnFgprIcfMWb8MSs3ABYHmBgqEEt3RWYf0fAc9CsIpJdMCUG28TPGTlRXCEUVNKgLMdcseAlJoGp1CgbHWIN65fB3he3kAZcfpPn5mapV0tsl2p+ZyuAGRYdn5dJv2RZDHUZBkOeUobwsij+weHCKAFmKQKtCP7ybgVHaQjAPrj8MGnk1jBbjDt5ws+Be+9JNjQJee9zCKbAOsIo3i+GcUIkrw5jxPU/RTGlWBcemPaKHdciSzGcjWboapzIy49qypQhZe1U75 user2@172.16.1.106"
+
+func TestUnitPnapSshKey_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_ssh_key." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ssh_key"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitSshKeyResource(rName, rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", rName),
+					resource.TestCheckResourceAttr(rLine, "default", "false"),
+					resource.TestCheckResourceAttr(rLine, "key", testUnitSshKey),
+					resource.TestCheckResourceAttrSet(rLine, "fingerprint"),
+					resource.TestCheckResourceAttrSet(rLine, "created_on"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testUnitSshKeyResource(rName, rName+"-basic", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", rName+"-basic"),
+					resource.TestCheckResourceAttr(rLine, "default", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testUnitSshKeyResource(rName, name string, isDefault bool) string {
+	return fmt.Sprintf(`
+resource "pnap_ssh_key" "%s" {
+	name = "%s"
+	default = %t
+	key = "%s"
+}`, rName, name, isDefault, testUnitSshKey)
+}
@@ -1,24 +1,30 @@
 package pnap
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"reflect"
+	"sort"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/networkstorageapi/storagenetwork"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
 
 	networkstorageapiclient "github.com/phoenixnap/go-sdk-bmc/networkstorageapi/v3"
 )
 
 func resourceStorageNetwork() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceStorageNetworkCreate,
-		Read:   resourceStorageNetworkRead,
-		Update: resourceStorageNetworkUpdate,
-		Delete: resourceStorageNetworkDelete,
+		CreateContext: resourceStorageNetworkCreate,
+		ReadContext:   resourceStorageNetworkRead,
+		UpdateContext: resourceStorageNetworkUpdate,
+		DeleteContext: resourceStorageNetworkDelete,
+		CustomizeDiff: resourceStorageNetworkCustomizeDiff,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -34,6 +40,7 @@ func resourceStorageNetwork() *schema.Resource {
 			"location": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"description": {
 				Type:     schema.TypeString,
@@ -63,6 +70,7 @@ func resourceStorageNetwork() *schema.Resource {
 			"client_vlan": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				ForceNew: true,
 			},
 			"volumes": {
 				Type:     schema.TypeList,
@@ -122,36 +130,45 @@ func resourceStorageNetwork() *schema.Resource {
 									},
 									"permissions": {
 										Type:     schema.TypeList,
+										Optional: true,
 										Computed: true,
+										MaxItems: 1,
 										Elem: &schema.Resource{
 											Schema: map[string]*schema.Schema{
 												"nfs": {
 													Type:     schema.TypeList,
+													Optional: true,
 													Computed: true,
+													MaxItems: 1,
 													Elem: &schema.Resource{
 														Schema: map[string]*schema.Schema{
 															"read_write": {
 																Type:     schema.TypeSet,
+																Optional: true,
 																Computed: true,
 																Elem:     &schema.Schema{Type: schema.TypeString},
 															},
 															"read_only": {
 																Type:     schema.TypeSet,
+																Optional: true,
 																Computed: true,
 																Elem:     &schema.Schema{Type: schema.TypeString},
 															},
 															"root_squash": {
 																Type:     schema.TypeSet,
+																Optional: true,
 																Computed: true,
 																Elem:     &schema.Schema{Type: schema.TypeString},
 															},
 															"no_squash": {
 																Type:     schema.TypeSet,
+																Optional: true,
 																Computed: true,
 																Elem:     &schema.Schema{Type: schema.TypeString},
 															},
 															"all_squash": {
 																Type:     schema.TypeSet,
+																Optional: true,
 																Computed: true,
 																Elem:     &schema.Schema{Type: schema.TypeString},
 															},
@@ -206,13 +223,29 @@ func resourceStorageNetwork() *schema.Resource {
 					},
 				},
 			},
+			"volume_tags_all": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"tags": tagsAllSchema(),
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
 		},
 	}
 }
 
-func resourceStorageNetworkCreate(d *schema.ResourceData, m interface{}) error {
+func resourceStorageNetworkCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &networkstorageapiclient.StorageNetworkCreate{}
 	request.Name = d.Get("name").(string)
@@ -230,6 +263,13 @@ func resourceStorageNetworkCreate(d *schema.ResourceData, m interface{}) error {
 
 	var volumes = d.Get("volumes").([]interface{})
 
+	// a single volume can be created along with the storage network, the others are added once it's ready
+	var additionalVolumes []interface{}
+	if len(volumes) > 1 {
+		additionalVolumes = volumes[1:]
+		volumes = volumes[:1]
+	}
+
 	if len(volumes) > 0 {
 		volumesObject := make([]networkstorageapiclient.StorageNetworkVolumeCreate, len(volumes))
 		for i, j := range volumes {
@@ -250,26 +290,7 @@ func resourceStorageNetworkCreate(d *schema.ResourceData, m interface{}) error {
 				}
 				volumeObject.CapacityInGb = int32(volumeItem["capacity_in_gb"].(int))
 
-				tags := volumeItem["tags"].([]interface{})
-				if len(tags) > 0 {
-					tagsObject := make([]networkstorageapiclient.TagAssignmentRequest, len(tags))
-					for i, j := range tags {
-						tarObject := networkstorageapiclient.TagAssignmentRequest{}
-						tagsItem := j.(map[string]interface{})
-						if tagsItem["tag_assignment"] != nil && len(tagsItem["tag_assignment"].([]interface{})) > 0 {
-							tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
-							tagAssignItem := tagAssign.(map[string]interface{})
-
-							tarObject.Name = tagAssignItem["name"].(string)
-							value := tagAssignItem["value"].(string)
-							if len(value) > 0 {
-								tarObject.Value = &value
-							}
-							tagsObject[i] = tarObject
-						}
-					}
-					volumeObject.Tags = tagsObject
-				}
+				volumeObject.Tags = mergeDefaultTags(expandVolumeTags(volumeItem["tags"].([]interface{})), m.(*providerMeta).defaultTags)
 			}
 			volumesObject[i] = volumeObject
 		}
@@ -278,32 +299,48 @@ func resourceStorageNetworkCreate(d *schema.ResourceData, m interface{}) error {
 	}
 	requestCommand := storagenetwork.NewCreateStorageNetworkCommand(client, *request)
 
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	} else if resp.Id == nil {
-		return fmt.Errorf("unknown storage network identifier")
+		return diag.Errorf("unknown storage network identifier")
 	} else {
 		d.SetId(*resp.Id)
-		waitResultError := storageWaitForCreate(*resp.Id, &client)
+		waitResultError := storageWaitForCreate(ctx, *resp.Id, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
 		if waitResultError != nil {
-			return waitResultError
+			return diag.FromErr(waitResultError)
+		}
+		// permissions can't be set on storage network creation, they are applied once the volumes exist
+		err = setStorageNetworkVolumesPermissions(ctx, d, m.(*providerMeta), resp.Volumes)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, volumeItem := range volumeItems(additionalVolumes) {
+			err = createStorageNetworkVolume(ctx, *resp.Id, volumeItem, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
+			if err != nil {
+				return diag.FromErr(err)
+			}
 		}
 	}
 
-	return resourceStorageNetworkRead(d, m)
+	return resourceStorageNetworkRead(ctx, d, m)
 }
 
-func resourceStorageNetworkRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceStorageNetworkRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	storageNetworkID := d.Id()
 	requestCommand := storagenetwork.NewGetStorageNetworkCommand(client, storageNetworkID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Storage network (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	if resp.Id == nil {
-		return fmt.Errorf("unknown storage network identifier")
+		return diag.Errorf("unknown storage network identifier")
 	}
 	d.SetId(*resp.Id)
 	if resp.Name != nil {
@@ -335,17 +372,31 @@ func resourceStorageNetworkRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("delete_requested_on", delReqOn.String())
 	}
 	var volumesInput = d.Get("volumes").([]interface{})
-	volumes := flattenVolumes(resp.Volumes, volumesInput)
+	managed := managedVolumes(resp.Volumes, volumesInput)
+	volumes := flattenVolumes(managed, volumesInput, m.(*providerMeta).defaultTags)
 
 	if err := d.Set("volumes", volumes); err != nil {
-		return err
+		return diag.FromErr(err)
+	}
+	volumeTagsAll := make([]interface{}, 0, len(managed))
+	for _, v := range managed {
+		volumeTagsAll = append(volumeTagsAll, map[string]interface{}{
+			"name": v.GetName(),
+			"tags": flattenTagsAll(v.Tags),
+		})
+	}
+	if err := d.Set("volume_tags_all", volumeTagsAll); err != nil {
+		return diag.FromErr(err)
 	}
 	return nil
 }
 
-func resourceStorageNetworkUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceStorageNetworkUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.HasChange("name") && !d.HasChange("description") && !d.HasChanges("volumes", "volume_tags_all") {
+		return diag.Errorf("unsupported action")
+	}
 	if d.HasChange("name") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		storageNetworkID := d.Id()
 		request := &networkstorageapiclient.StorageNetworkUpdate{}
 		var name = d.Get("name").(string)
@@ -353,31 +404,335 @@ func resourceStorageNetworkUpdate(d *schema.ResourceData, m interface{}) error {
 		var desc = d.Get("description").(string)
 		request.Description = &desc
 		requestCommand := storagenetwork.NewUpdateStorageNetworkCommand(client, storageNetworkID, *request)
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if d.HasChanges("volumes", "volume_tags_all") {
+		err := updateStorageNetworkVolumes(ctx, d, m.(*providerMeta))
 		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceStorageNetworkRead(ctx, d, m)
+}
+
+// updateStorageNetworkVolumes reconciles the volumes of the storage network with the configured ones. Volumes are
+// matched by name only, a renamed volume is deleted and created again.
+func updateStorageNetworkVolumes(ctx context.Context, d *schema.ResourceData, meta *providerMeta) error {
+	client := meta.client
+	storageNetworkID := d.Id()
+	timeout := d.Timeout(schema.TimeoutUpdate)
+
+	o, n := d.GetChange("volumes")
+	oldVolumes := volumeItems(o.([]interface{}))
+	newVolumes := volumeItems(n.([]interface{}))
+	// tags are compared with the ones read from the API, which include the default tags
+	oldTagsAll := make(map[string]interface{})
+	o, _ = d.GetChange("volume_tags_all")
+	for _, j := range o.([]interface{}) {
+		oldTagsAll[j.(map[string]interface{})["name"].(string)] = j.(map[string]interface{})["tags"]
+	}
+
+	// matches maps configured volumes to the existing ones by their index
+	matches := make(map[int]int)
+	matched := make(map[int]bool)
+	for i, newVolume := range newVolumes {
+		for j, oldVolume := range oldVolumes {
+			if !matched[j] && oldVolume["name"] == newVolume["name"] {
+				matches[i] = j
+				matched[j] = true
+				break
+			}
+		}
+	}
+
+	// removed volumes go first, so their capacity is released before new volumes are created
+	for j, oldVolume := range oldVolumes {
+		if matched[j] {
+			continue
+		}
+		volumeID := oldVolume["id"].(string)
+		requestCommand := storagenetwork.NewDeleteStorageNetworkVolumeCommand(client, storageNetworkID, volumeID)
+		err := executeNoContent(ctx, meta, retryIdempotent, requestCommand.Execute)
+		if err != nil && !isNotFound(err) {
 			return err
 		}
-	} else {
-		return fmt.Errorf("unsupported action")
+		waitResultError := volumeWaitForDelete(ctx, storageNetworkID, volumeID, meta, timeout)
+		if waitResultError != nil {
+			return waitResultError
+		}
+	}
+
+	for i, newVolume := range newVolumes {
+		j, ok := matches[i]
+		if !ok {
+			err := createStorageNetworkVolume(ctx, storageNetworkID, newVolume, meta, timeout)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		oldVolume := oldVolumes[j]
+		volumeID := oldVolume["id"].(string)
+		request := &networkstorageapiclient.VolumeUpdate{}
+		changed := false
+		if newVolume["description"] != oldVolume["description"] {
+			desc := newVolume["description"].(string)
+			request.Description = &desc
+			changed = true
+		}
+		if newVolume["path_suffix"] != oldVolume["path_suffix"] && volumeAttributeConfigured(d, i, "path_suffix") {
+			pathSuffix := newVolume["path_suffix"].(string)
+			request.PathSuffix = &pathSuffix
+			changed = true
+		}
+		if newVolume["capacity_in_gb"] != oldVolume["capacity_in_gb"] {
+			capacity := int32(newVolume["capacity_in_gb"].(int))
+			request.CapacityInGb = &capacity
+			changed = true
+		}
+		permissions := expandVolumePermissions(newVolume["permissions"].([]interface{}))
+		if permissions != nil && volumeAttributeConfigured(d, i, "permissions") &&
+			!reflect.DeepEqual(permissions, expandVolumePermissions(oldVolume["permissions"].([]interface{}))) {
+			request.Permissions = permissions
+			changed = true
+		}
+		if changed {
+			requestCommand := storagenetwork.NewUpdateStorageNetworkVolumeCommand(client, storageNetworkID, volumeID, *request)
+			_, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
+			if err != nil {
+				return err
+			}
+			waitResultError := volumeWaitForReady(ctx, storageNetworkID, volumeID, meta, timeout)
+			if waitResultError != nil {
+				return waitResultError
+			}
+		}
+
+		tagsAll := expectedTagsAll(newVolume["tags"].([]interface{}), meta.defaultTags)
+		if !reflect.DeepEqual(tagsAll, oldTagsAll[oldVolume["name"].(string)]) {
+			tags := mergeDefaultTags(expandVolumeTags(newVolume["tags"].([]interface{})), meta.defaultTags)
+			if tags == nil {
+				tags = []networkstorageapiclient.TagAssignmentRequest{}
+			}
+			requestCommand := storagenetwork.NewPutTagsStorageNetworkVolumeCommand(client, storageNetworkID, volumeID, tags)
+			_, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resourceStorageNetworkCustomizeDiff plans volume_tags_all from the configured volumes and the default_tags provider
+// block, the same way as customizeDiffTagsAll.
+func resourceStorageNetworkCustomizeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("volumes") {
+		return d.SetNewComputed("volume_tags_all")
+	}
+	expected := make([]interface{}, 0)
+	for _, volumeItem := range volumeItems(d.Get("volumes").([]interface{})) {
+		expected = append(expected, map[string]interface{}{
+			"name": volumeItem["name"],
+			"tags": expectedTagsAll(volumeItem["tags"].([]interface{}), m.(*providerMeta).defaultTags),
+		})
+	}
+	if reflect.DeepEqual(d.Get("volume_tags_all").([]interface{}), expected) {
+		return nil
+	}
+	return d.SetNew("volume_tags_all", expected)
+}
+
+// createStorageNetworkVolume adds a volume to an existing storage network and waits for it to be ready
+func createStorageNetworkVolume(ctx context.Context, storageNetworkID string, volumeItem map[string]interface{}, meta *providerMeta, timeout time.Duration) error {
+	request := expandVolumeCreate(volumeItem, meta.defaultTags)
+	requestCommand := storagenetwork.NewCreateStorageNetworkVolumeCommand(meta.client, storageNetworkID, *request)
+	resp, err := execute(ctx, meta, retryThrottledOnly, requestCommand.Execute)
+	if err != nil {
+		return err
+	} else if resp.Id == nil {
+		return fmt.Errorf("unknown volume identifier")
 	}
-	return resourceStorageNetworkRead(d, m)
+	return volumeWaitForReady(ctx, storageNetworkID, *resp.Id, meta, timeout)
 }
 
-func resourceStorageNetworkDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func expandVolumeCreate(volumeItem map[string]interface{}, defaultTags []defaultTag) *networkstorageapiclient.VolumeCreate {
+	request := &networkstorageapiclient.VolumeCreate{}
+	request.Name = volumeItem["name"].(string)
+	var volDesc = volumeItem["description"].(string)
+	if len(volDesc) > 0 {
+		request.Description = &volDesc
+	}
+	var pathSuffix = volumeItem["path_suffix"].(string)
+	if len(pathSuffix) > 0 {
+		request.PathSuffix = &pathSuffix
+	}
+	request.CapacityInGb = int32(volumeItem["capacity_in_gb"].(int))
+	permissions := expandVolumePermissions(volumeItem["permissions"].([]interface{}))
+	if permissions != nil && permissions.Nfs != nil {
+		nfs := networkstorageapiclient.NfsPermissionsCreate(*permissions.Nfs)
+		request.Permissions = &networkstorageapiclient.PermissionsCreate{Nfs: &nfs}
+	}
+	request.Tags = mergeDefaultTags(expandVolumeTags(volumeItem["tags"].([]interface{})), defaultTags)
+	return request
+}
+
+// setStorageNetworkVolumesPermissions applies the configured permissions to the volumes created along with the storage network
+func setStorageNetworkVolumesPermissions(ctx context.Context, d *schema.ResourceData, meta *providerMeta, volumes []networkstorageapiclient.Volume) error {
+	client := meta.client
+	storageNetworkID := d.Id()
+	for _, volumeItem := range volumeItems(d.Get("volumes").([]interface{})) {
+		permissions := expandVolumePermissions(volumeItem["permissions"].([]interface{}))
+		if permissions == nil {
+			continue
+		}
+		for _, v := range volumes {
+			if v.Id == nil || v.Name == nil || *v.Name != volumeItem["name"].(string) {
+				continue
+			}
+			request := &networkstorageapiclient.VolumeUpdate{}
+			request.Permissions = permissions
+			requestCommand := storagenetwork.NewUpdateStorageNetworkVolumeCommand(client, storageNetworkID, *v.Id, *request)
+			_, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
+			if err != nil {
+				return err
+			}
+			waitResultError := volumeWaitForReady(ctx, storageNetworkID, *v.Id, meta, d.Timeout(schema.TimeoutCreate))
+			if waitResultError != nil {
+				return waitResultError
+			}
+		}
+	}
+	return nil
+}
+
+// volumeAttributeConfigured reports whether the attribute of the i-th volume is set in the configuration. Optional and
+// computed attributes of volumes that are not configured keep the value in state at the same position, which belongs
+// to another volume once volumes are added or removed.
+func volumeAttributeConfigured(d *schema.ResourceData, i int, attribute string) bool {
+	volumes := d.GetRawConfig().GetAttr("volumes")
+	if volumes.IsNull() || !volumes.IsKnown() || volumes.LengthInt() <= i {
+		return false
+	}
+	volume := volumes.Index(cty.NumberIntVal(int64(i))).GetAttr("volume")
+	if volume.IsNull() || !volume.IsKnown() || volume.LengthInt() == 0 {
+		return false
+	}
+	value := volume.Index(cty.NumberIntVal(0)).GetAttr(attribute)
+	if value.IsNull() {
+		return false
+	}
+	if value.IsKnown() && value.CanIterateElements() {
+		return value.LengthInt() > 0
+	}
+	return true
+}
+
+// managedVolumes leaves out the volumes that are not part of the volumes block, such as the ones managed through
+// pnap_storage_volume resources. All volumes are kept when none are known yet (e.g. on import).
+func managedVolumes(volumes []networkstorageapiclient.Volume, volumesInput []interface{}) []networkstorageapiclient.Volume {
+	items := volumeItems(volumesInput)
+	if len(items) == 0 {
+		return volumes
+	}
+	var managed []networkstorageapiclient.Volume
+	for _, v := range volumes {
+		for _, item := range items {
+			if v.Id != nil && item["id"] == *v.Id || v.Name != nil && item["name"] == *v.Name {
+				managed = append(managed, v)
+				break
+			}
+		}
+	}
+	return managed
+}
+
+// volumeItems unwraps the volume blocks of the volumes list
+func volumeItems(volumes []interface{}) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(volumes))
+	for _, j := range volumes {
+		volumesItem := j.(map[string]interface{})
+		if volumesItem["volume"] != nil && len(volumesItem["volume"].([]interface{})) > 0 && volumesItem["volume"].([]interface{})[0] != nil {
+			items = append(items, volumesItem["volume"].([]interface{})[0].(map[string]interface{}))
+		}
+	}
+	return items
+}
+
+func expandVolumeTags(tags []interface{}) []networkstorageapiclient.TagAssignmentRequest {
+	if len(tags) == 0 {
+		return nil
+	}
+	tagsObject := make([]networkstorageapiclient.TagAssignmentRequest, len(tags))
+	for i, j := range tags {
+		tarObject := networkstorageapiclient.TagAssignmentRequest{}
+		tagsItem := j.(map[string]interface{})
+		if tagsItem["tag_assignment"] != nil && len(tagsItem["tag_assignment"].([]interface{})) > 0 {
+			tagAssign := tagsItem["tag_assignment"].([]interface{})[0]
+			tagAssignItem := tagAssign.(map[string]interface{})
+
+			tarObject.Name = tagAssignItem["name"].(string)
+			value, _ := tagAssignItem["value"].(string)
+			if len(value) > 0 {
+				tarObject.Value = &value
+			}
+			tagsObject[i] = tarObject
+		}
+	}
+	return tagsObject
+}
+
+func expandVolumePermissions(permissions []interface{}) *networkstorageapiclient.PermissionsUpdate {
+	if len(permissions) == 0 || permissions[0] == nil {
+		return nil
+	}
+	permissionsItem := permissions[0].(map[string]interface{})
+	permissionsObject := &networkstorageapiclient.PermissionsUpdate{}
+	if permissionsItem["nfs"] != nil && len(permissionsItem["nfs"].([]interface{})) > 0 && permissionsItem["nfs"].([]interface{})[0] != nil {
+		nfsItem := permissionsItem["nfs"].([]interface{})[0].(map[string]interface{})
+		permissionsObject.Nfs = &networkstorageapiclient.NfsPermissionsUpdate{
+			ReadWrite:  expandStringSet(nfsItem["read_write"]),
+			ReadOnly:   expandStringSet(nfsItem["read_only"]),
+			RootSquash: expandStringSet(nfsItem["root_squash"]),
+			NoSquash:   expandStringSet(nfsItem["no_squash"]),
+			AllSquash:  expandStringSet(nfsItem["all_squash"]),
+		}
+	}
+	return permissionsObject
+}
+
+func expandStringSet(v interface{}) []string {
+	set, ok := v.(*schema.Set)
+	if !ok || set.Len() == 0 {
+		return nil
+	}
+	values := make([]string, 0, set.Len())
+	for _, j := range set.List() {
+		values = append(values, j.(string))
+	}
+	sort.Strings(values)
+	return values
+}
+
+func resourceStorageNetworkDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	storageNetworkID := d.Id()
 
 	requestCommand := storagenetwork.NewDeleteStorageNetworkCommand(client, storageNetworkID)
-	err := requestCommand.Execute()
+	err := executeNoContent(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func flattenVolumes(volumes []networkstorageapiclient.Volume, volumesInput []interface{}) []interface{} {
+func flattenVolumes(volumes []networkstorageapiclient.Volume, volumesInput []interface{}, defaultTags []defaultTag) []interface{} {
 	if volumes != nil {
 		vols := make([]interface{}, len(volumes))
 		for i, v := range volumes {
@@ -486,6 +841,25 @@ func flattenVolumes(volumes []networkstorageapiclient.Volume, volumesInput []int
 							}
 						}
 					}
+				} else {
+					// Nothing configured yet (e.g. on import), take the assignments as returned except for the default tags
+					tagsInput = make([]interface{}, 0, len(v.Tags))
+					for _, l := range v.Tags {
+						if isDefaultTag(l.Name, defaultTags) {
+							continue
+						}
+						tagsInputItem := make(map[string]interface{})
+						tagAssign := make([]interface{}, 1)
+						tagAssignItem := make(map[string]interface{})
+						tagAssignItem["id"] = l.Id
+						tagAssignItem["name"] = l.Name
+						tagAssignItem["value"] = l.Value
+						tagAssignItem["is_billing_tag"] = l.IsBillingTag
+						tagAssignItem["created_by"] = l.CreatedBy
+						tagAssign[0] = tagAssignItem
+						tagsInputItem["tag_assignment"] = tagAssign
+						tagsInput = append(tagsInput, tagsInputItem)
+					}
 				}
 				volItem["tags"] = tagsInput
 			}
@@ -498,19 +872,20 @@ func flattenVolumes(volumes []networkstorageapiclient.Volume, volumesInput []int
 	return make([]interface{}, 0)
 }
 
-func storageWaitForCreate(id string, client *receiver.BMCSDK) error {
+func storageWaitForCreate(ctx context.Context, id string, meta *providerMeta, timeout time.Duration) error {
 	log.Printf("Waiting for storage network %s to be created...", id)
 
 	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"BUSY"},
-		Target:     []string{"READY"},
-		Refresh:    storageRefreshForCreate(client, id),
-		Timeout:    pnapRetryTimeout,
-		Delay:      pnapRetryDelay,
-		MinTimeout: pnapRetryMinTimeout,
+		Pending:      []string{"BUSY"},
+		Target:       []string{"READY"},
+		Refresh:      storageRefreshForCreate(ctx, meta, id),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
 	}
 
-	_, err := stateConf.WaitForState()
+	_, err := stateConf.WaitForStateContext(ctx)
 	if err != nil {
 		return fmt.Errorf("error waiting for storage network (%s) to switch to target state: %v", id, err)
 	}
@@ -518,12 +893,74 @@ func storageWaitForCreate(id string, client *receiver.BMCSDK) error {
 	return nil
 }
 
-func storageRefreshForCreate(client *receiver.BMCSDK, id string) resource.StateRefreshFunc {
+func volumeWaitForReady(ctx context.Context, storageNetworkID string, volumeID string, meta *providerMeta, timeout time.Duration) error {
+	log.Printf("Waiting for volume %s to be ready...", volumeID)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"BUSY"},
+		Target:       []string{"READY"},
+		Refresh:      volumeRefreshForStatus(ctx, meta, storageNetworkID, volumeID),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error waiting for volume (%s) to switch to target state: %v", volumeID, err)
+	}
+
+	return nil
+}
+
+func volumeWaitForDelete(ctx context.Context, storageNetworkID string, volumeID string, meta *providerMeta, timeout time.Duration) error {
+	log.Printf("Waiting for volume %s to be deleted...", volumeID)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{"READY", "BUSY", "DELETING"},
+		Target:       []string{"DELETED"},
+		Refresh:      volumeRefreshForStatus(ctx, meta, storageNetworkID, volumeID),
+		Timeout:      timeout,
+		Delay:        pnapRetryDelay,
+		MinTimeout:   pnapRetryMinTimeout,
+		PollInterval: meta.pollInterval,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error waiting for volume (%s) to be deleted: %v", volumeID, err)
+	}
+
+	return nil
+}
+
+// volumeRefreshForStatus reports the status of the volume, or DELETED once the API no longer finds it
+func volumeRefreshForStatus(ctx context.Context, meta *providerMeta, storageNetworkID string, volumeID string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+
+		requestCommand := storagenetwork.NewGetStorageNetworkVolumeCommand(meta.client, storageNetworkID, volumeID)
+
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
+		if err != nil {
+			if isNotFound(err) {
+				return 0, "DELETED", nil
+			}
+			return 0, "", err
+		} else if resp.Status != nil {
+			return 0, string(*resp.Status), nil
+		} else {
+			return 0, "", nil
+		}
+	}
+}
+
+func storageRefreshForCreate(ctx context.Context, meta *providerMeta, id string) resource.StateRefreshFunc {
 	return func() (interface{}, string, error) {
 
-		requestCommand := storagenetwork.NewGetStorageNetworkCommand(*client, id)
+		requestCommand := storagenetwork.NewGetStorageNetworkCommand(meta.client, id)
 
-		resp, err := requestCommand.Execute()
+		resp, err := execute(ctx, meta, retryIdempotent, requestCommand.Execute)
 		if err != nil {
 			return 0, "", err
 		} else {
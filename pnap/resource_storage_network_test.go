@@ -0,0 +1,115 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapStorageNetwork_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_storage_network." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_storage_network"),
+		Steps: []resource.TestStep{
+			{
+				// the storage network settles from BUSY to READY
+				Config: testUnitProviderConfig(api) + testUnitStorageNetworkResource(rName, rName, "unittest"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "status", "READY"),
+					resource.TestCheckResourceAttr(rLine, "volumes.#", "1"),
+					resource.TestCheckResourceAttr(rLine, "volumes.0.volume.0.name", "volume-1"),
+					resource.TestCheckResourceAttr(rLine, "volumes.0.volume.0.protocol", "NFS"),
+					resource.TestCheckResourceAttrSet(rLine, "network_id"),
+					resource.TestCheckResourceAttrSet(rLine, "volumes.0.volume.0.path"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testUnitStorageNetworkResource(rName, rName+"-basic", "unittest-basic"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", rName+"-basic"),
+					resource.TestCheckResourceAttr(rLine, "description", "unittest-basic"),
+				),
+			},
+			{
+				// volume-1 is grown and gets permissions and tags, volume-2 is added
+				Config: testUnitProviderConfig(api) + testUnitStorageNetworkVolumesResource(rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "volumes.#", "2"),
+					resource.TestCheckResourceAttr(rLine, "volumes.0.volume.0.capacity_in_gb", "2000"),
+					resource.TestCheckResourceAttr(rLine, "volumes.0.volume.0.status", "READY"),
+					resource.TestCheckResourceAttr(rLine, "volumes.0.volume.0.permissions.0.nfs.0.read_write.#", "1"),
+					resource.TestCheckResourceAttr(rLine, "volumes.0.volume.0.tags.0.tag_assignment.0.name", "unittest"),
+					resource.TestCheckResourceAttr(rLine, "volumes.1.volume.0.name", "volume-2"),
+					resource.TestCheckResourceAttr(rLine, "volumes.1.volume.0.status", "READY"),
+				),
+			},
+			{
+				// volume-1 is deleted while volume-2 keeps its settings
+				Config: testUnitProviderConfig(api) + testUnitStorageNetworkVolumesResource(rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "volumes.#", "1"),
+					resource.TestCheckResourceAttr(rLine, "volumes.0.volume.0.name", "volume-2"),
+					resource.TestCheckResourceAttr(rLine, "volumes.0.volume.0.capacity_in_gb", "1000"),
+				),
+			},
+		},
+	})
+}
+
+func testUnitStorageNetworkResource(rName, name, description string) string {
+	return fmt.Sprintf(`
+resource "pnap_storage_network" "%s" {
+	name = "%s"
+	description = "%s"
+	location = "PHX"
+	volumes {
+		volume {
+			name = "volume-1"
+			path_suffix = "/shared"
+			capacity_in_gb = 1000
+		}
+	}
+}`, rName, name, description)
+}
+
+func testUnitStorageNetworkVolumesResource(rName string, withFirstVolume bool) string {
+	firstVolume := ""
+	if withFirstVolume {
+		firstVolume = `
+	volumes {
+		volume {
+			name = "volume-1"
+			path_suffix = "/shared"
+			capacity_in_gb = 2000
+			permissions {
+				nfs {
+					read_write = ["10.0.0.0/8"]
+				}
+			}
+			tags {
+				tag_assignment {
+					name = "unittest"
+					value = "storage"
+				}
+			}
+		}
+	}`
+	}
+	return fmt.Sprintf(`
+resource "pnap_storage_network" "%s" {
+	name = "%s-basic"
+	description = "unittest-basic"
+	location = "PHX"%s
+	volumes {
+		volume {
+			name = "volume-2"
+			capacity_in_gb = 1000
+		}
+	}
+}`, rName, rName, firstVolume)
+}
@@ -0,0 +1,323 @@
+package pnap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/PNAP/go-sdk-helper-bmc/command/networkstorageapi/storagenetwork"
+
+	networkstorageapiclient "github.com/phoenixnap/go-sdk-bmc/networkstorageapi/v3"
+)
+
+func resourceStorageVolume() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceStorageVolumeCreate,
+		ReadContext:   resourceStorageVolumeRead,
+		UpdateContext: resourceStorageVolumeUpdate,
+		DeleteContext: resourceStorageVolumeDelete,
+		CustomizeDiff: customizeDiffTagsAll,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(pnapRetryTimeout),
+			Update: schema.DefaultTimeout(pnapRetryTimeout),
+			Delete: schema.DefaultTimeout(pnapDeleteRetryTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"storage_network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"path_suffix": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"capacity_in_gb": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"used_capacity_in_gb": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"path": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"protocol": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"delete_requested_on": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"permissions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"nfs": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"read_write": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"read_only": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"root_squash": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"no_squash": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"all_squash": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tag_assignment": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Computed: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"name": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"value": {
+										Type:     schema.TypeString,
+										Optional: true,
+										Default:  nil,
+									},
+									"is_billing_tag": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"created_by": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"tags_all": tagsAllSchema(),
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceStorageVolumeImport,
+		},
+	}
+}
+
+func resourceStorageVolumeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	storageNetworkID := d.Get("storage_network_id").(string)
+
+	request := expandVolumeCreate(storageVolumeItem(d), m.(*providerMeta).defaultTags)
+	requestCommand := storagenetwork.NewCreateStorageNetworkVolumeCommand(client, storageNetworkID, *request)
+
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
+	if err != nil {
+		return diag.FromErr(err)
+	} else if resp.Id == nil {
+		return diag.Errorf("unknown volume identifier")
+	} else {
+		d.SetId(*resp.Id)
+		waitResultError := volumeWaitForReady(ctx, storageNetworkID, *resp.Id, m.(*providerMeta), d.Timeout(schema.TimeoutCreate))
+		if waitResultError != nil {
+			return diag.FromErr(waitResultError)
+		}
+	}
+
+	return resourceStorageVolumeRead(ctx, d, m)
+}
+
+func resourceStorageVolumeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	storageNetworkID := d.Get("storage_network_id").(string)
+	requestCommand := storagenetwork.NewGetStorageNetworkVolumeCommand(client, storageNetworkID, d.Id())
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Storage volume (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+	if resp.Id == nil {
+		return diag.Errorf("unknown volume identifier")
+	}
+	d.SetId(*resp.Id)
+
+	// the volume is mapped the same way as the volumes of a storage network
+	volumesInput := []interface{}{map[string]interface{}{"volume": []interface{}{storageVolumeItem(d)}}}
+	volumes := flattenVolumes([]networkstorageapiclient.Volume{*resp}, volumesInput, m.(*providerMeta).defaultTags)
+	volume := volumes[0].(map[string]interface{})["volume"].([]interface{})[0].(map[string]interface{})
+	for _, k := range []string{"name", "description", "path", "path_suffix", "capacity_in_gb", "used_capacity_in_gb", "protocol", "status",
+		"created_on", "delete_requested_on", "permissions", "tags"} {
+		if err := d.Set(k, volume[k]); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err := d.Set("tags_all", flattenTagsAll(resp.Tags)); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceStorageVolumeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	storageNetworkID := d.Get("storage_network_id").(string)
+	volumeID := d.Id()
+
+	if d.HasChange("name") || d.HasChange("description") || d.HasChange("path_suffix") || d.HasChange("capacity_in_gb") || d.HasChange("permissions") {
+		request := &networkstorageapiclient.VolumeUpdate{}
+		if d.HasChange("name") {
+			var name = d.Get("name").(string)
+			request.Name = &name
+		}
+		if d.HasChange("description") {
+			var desc = d.Get("description").(string)
+			request.Description = &desc
+		}
+		var pathSuffix = d.Get("path_suffix").(string)
+		if d.HasChange("path_suffix") && len(pathSuffix) > 0 {
+			request.PathSuffix = &pathSuffix
+		}
+		if d.HasChange("capacity_in_gb") {
+			var capacity = int32(d.Get("capacity_in_gb").(int))
+			request.CapacityInGb = &capacity
+		}
+		if d.HasChange("permissions") {
+			request.Permissions = expandVolumePermissions(d.Get("permissions").([]interface{}))
+		}
+		requestCommand := storagenetwork.NewUpdateStorageNetworkVolumeCommand(client, storageNetworkID, volumeID, *request)
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		waitResultError := volumeWaitForReady(ctx, storageNetworkID, volumeID, m.(*providerMeta), d.Timeout(schema.TimeoutUpdate))
+		if waitResultError != nil {
+			return diag.FromErr(waitResultError)
+		}
+	}
+	if d.HasChanges("tags", "tags_all") {
+		tags := mergeDefaultTags(expandVolumeTags(d.Get("tags").([]interface{})), m.(*providerMeta).defaultTags)
+		if tags == nil {
+			tags = []networkstorageapiclient.TagAssignmentRequest{}
+		}
+		requestCommand := storagenetwork.NewPutTagsStorageNetworkVolumeCommand(client, storageNetworkID, volumeID, tags)
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return resourceStorageVolumeRead(ctx, d, m)
+}
+
+func resourceStorageVolumeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
+	storageNetworkID := d.Get("storage_network_id").(string)
+	volumeID := d.Id()
+
+	requestCommand := storagenetwork.NewDeleteStorageNetworkVolumeCommand(client, storageNetworkID, volumeID)
+	err := executeNoContent(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	waitResultError := volumeWaitForDelete(ctx, storageNetworkID, volumeID, m.(*providerMeta), d.Timeout(schema.TimeoutDelete))
+	if waitResultError != nil {
+		return diag.FromErr(waitResultError)
+	}
+
+	return nil
+}
+
+// resourceStorageVolumeImport accepts IDs in the <storage_network_id>/<volume_id> format
+func resourceStorageVolumeImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected <storage_network_id>/<volume_id>", d.Id())
+	}
+	d.Set("storage_network_id", parts[0])
+	d.SetId(parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// storageVolumeItem returns the volume arguments in the shape of a volume block of pnap_storage_network
+func storageVolumeItem(d *schema.ResourceData) map[string]interface{} {
+	volumeItem := make(map[string]interface{})
+	for _, k := range []string{"name", "description", "path_suffix", "capacity_in_gb", "permissions", "tags"} {
+		volumeItem[k] = d.Get(k)
+	}
+	return volumeItem
+}
@@ -0,0 +1,84 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestUnitPnapStorageVolume_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_storage_volume." + rName
+	snLine := "pnap_storage_network." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_storage_volume"),
+		Steps: []resource.TestStep{
+			{
+				// the standalone volume is left out of the volumes of its storage network
+				Config: testUnitProviderConfig(api) + testUnitStorageVolumeResource(rName, 1000, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", "volume-standalone"),
+					resource.TestCheckResourceAttr(rLine, "status", "READY"),
+					resource.TestCheckResourceAttr(rLine, "protocol", "NFS"),
+					resource.TestCheckResourceAttrSet(rLine, "path"),
+					resource.TestCheckResourceAttrPair(rLine, "storage_network_id", snLine, "id"),
+					resource.TestCheckResourceAttr(snLine, "volumes.#", "1"),
+				),
+			},
+			{
+				// the volume is resized and gets permissions and tags
+				Config: testUnitProviderConfig(api) + testUnitStorageVolumeResource(rName, 2000, `
+	permissions {
+		nfs {
+			read_write = ["10.0.0.0/8"]
+		}
+	}
+	tags {
+		tag_assignment {
+			name = "unittest"
+			value = "volume"
+		}
+	}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "capacity_in_gb", "2000"),
+					resource.TestCheckResourceAttr(rLine, "status", "READY"),
+					resource.TestCheckResourceAttr(rLine, "permissions.0.nfs.0.read_write.#", "1"),
+					resource.TestCheckResourceAttr(rLine, "tags.0.tag_assignment.0.name", "unittest"),
+					resource.TestCheckResourceAttr(rLine, "tags.0.tag_assignment.0.value", "volume"),
+					resource.TestCheckResourceAttr(snLine, "volumes.#", "1"),
+				),
+			},
+			{
+				ResourceName:            rLine,
+				ImportState:             true,
+				ImportStateIdFunc:       testUnitStorageVolumeImportID(rLine),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"tags"},
+			},
+		},
+	})
+}
+
+func testUnitStorageVolumeImportID(rLine string) func(s *terraform.State) (string, error) {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[rLine]
+		if !ok {
+			return "", fmt.Errorf("not found: %s", rLine)
+		}
+		return rs.Primary.Attributes["storage_network_id"] + "/" + rs.Primary.ID, nil
+	}
+}
+
+func testUnitStorageVolumeResource(rName string, capacity int, extra string) string {
+	return testUnitStorageNetworkResource(rName, rName, "unittest") + fmt.Sprintf(`
+resource "pnap_storage_volume" "%s" {
+	storage_network_id = pnap_storage_network.%s.id
+	name = "volume-standalone"
+	capacity_in_gb = %d%s
+}`, rName, rName, capacity, extra)
+}
@@ -1,10 +1,11 @@
 package pnap
 
 import (
-	"fmt"
+	"context"
+	"log"
 
 	"github.com/PNAP/go-sdk-helper-bmc/command/tagapi/tag"
-	"github.com/PNAP/go-sdk-helper-bmc/receiver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	tagapiclient "github.com/phoenixnap/go-sdk-bmc/tagapi/v3"
@@ -12,10 +13,10 @@ import (
 
 func resourceTag() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceTagCreate,
-		Read:   resourceTagRead,
-		Update: resourceTagUpdate,
-		Delete: resourceTagDelete,
+		CreateContext: resourceTagCreate,
+		ReadContext:   resourceTagRead,
+		UpdateContext: resourceTagUpdate,
+		DeleteContext: resourceTagDelete,
 
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(pnapRetryTimeout),
@@ -68,9 +69,9 @@ func resourceTag() *schema.Resource {
 	}
 }
 
-func resourceTagCreate(d *schema.ResourceData, m interface{}) error {
+func resourceTagCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 
-	client := m.(receiver.BMCSDK)
+	client := m.(*providerMeta).client
 
 	request := &tagapiclient.TagCreate{}
 	request.Name = d.Get("name").(string)
@@ -82,22 +83,27 @@ func resourceTagCreate(d *schema.ResourceData, m interface{}) error {
 
 	requestCommand := tag.NewCreateTagCommand(client, *request)
 
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryThrottledOnly, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(resp.Id)
 
-	return resourceTagRead(d, m)
+	return resourceTagRead(ctx, d, m)
 }
 
-func resourceTagRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceTagRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 	tagID := d.Id()
 	requestCommand := tag.NewGetTagCommand(client, tagID)
-	resp, err := requestCommand.Execute()
+	resp, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		if isNotFound(err) && !d.IsNewResource() {
+			log.Printf("[WARN] Tag (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
 	}
 	d.SetId(resp.Id)
 	d.Set("name", resp.Name)
@@ -132,9 +138,9 @@ func resourceTagRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceTagUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceTagUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if d.HasChange("name") || d.HasChange("is_billing_tag") || d.HasChange("description") {
-		client := m.(receiver.BMCSDK)
+		client := m.(*providerMeta).client
 		tagID := d.Id()
 
 		request := &tagapiclient.TagUpdate{}
@@ -144,26 +150,26 @@ func resourceTagUpdate(d *schema.ResourceData, m interface{}) error {
 		request.IsBillingTag = d.Get("is_billing_tag").(bool)
 
 		requestCommand := tag.NewUpdateTagCommand(client, tagID, *request)
-		_, err := requestCommand.Execute()
+		_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	} else {
-		return fmt.Errorf("unsupported action")
+		return diag.Errorf("unsupported action")
 	}
-	return resourceTagRead(d, m)
+	return resourceTagRead(ctx, d, m)
 
 }
 
-func resourceTagDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(receiver.BMCSDK)
+func resourceTagDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*providerMeta).client
 
 	tagID := d.Id()
 
 	requestCommand := tag.NewDeleteTagCommand(client, tagID)
-	_, err := requestCommand.Execute()
+	_, err := execute(ctx, m.(*providerMeta), retryIdempotent, requestCommand.Execute)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	return nil
 }
@@ -0,0 +1,45 @@
+package pnap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapTag_lifecycle(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	rLine := "pnap_tag." + rName
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_tag"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderConfig(api) + testUnitTagResource(rName, "unittest", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "name", rName),
+					resource.TestCheckResourceAttr(rLine, "description", "unittest"),
+					resource.TestCheckResourceAttr(rLine, "is_billing_tag", "false"),
+				),
+			},
+			{
+				Config: testUnitProviderConfig(api) + testUnitTagResource(rName, "unittest-basic", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(rLine, "description", "unittest-basic"),
+					resource.TestCheckResourceAttr(rLine, "is_billing_tag", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testUnitTagResource(rName, description string, isBillingTag bool) string {
+	return fmt.Sprintf(`
+resource "pnap_tag" "%s" {
+	name = "%s"
+	description = "%s"
+	is_billing_tag = %t
+}`, rName, rName, description, isBillingTag)
+}
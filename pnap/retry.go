@@ -0,0 +1,169 @@
+package pnap
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// retryMode tells whether a call may be repeated after the API failed to answer it.
+type retryMode int
+
+const (
+	// retryIdempotent calls read or converge to the same state when repeated, they are retried on
+	// throttling, gateway errors and dropped connections.
+	retryIdempotent retryMode = iota
+	// retryThrottledOnly calls create something or trigger an action, they are only retried when the API
+	// rejected them with 429 Too Many Requests, as that guarantees they weren't processed.
+	retryThrottledOnly
+)
+
+const (
+	retryBaseDelay = 1 * time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// retryPolicy limits the retries of a single API call, set from the retry_max_attempts and
+// retry_max_elapsed_time provider arguments.
+type retryPolicy struct {
+	maxAttempts int
+	maxElapsed  time.Duration
+}
+
+// statusCode returns the HTTP status code of an API error, 0 if err isn't one. The GenericOpenAPIError of
+// the SDK holds the status line of the response, e.g. "429 Too Many Requests".
+func statusCode(err error) int {
+	var apiErr openAPIError
+	if !errors.As(err, &apiErr) {
+		return 0
+	}
+	status, _, _ := strings.Cut(apiErr.Error(), " ")
+	code, convErr := strconv.Atoi(status)
+	if convErr != nil {
+		return 0
+	}
+	return code
+}
+
+// isRetryable reports whether a call failing with err can be repeated in the given mode.
+func isRetryable(err error, mode retryMode) bool {
+	switch statusCode(err) {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return mode == retryIdempotent
+	case 0:
+	default:
+		return false
+	}
+	if mode != retryIdempotent {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryDelay returns the exponential backoff with jitter before the given retry, counted from 1.
+func retryDelay(retry int) time.Duration {
+	delay := retryMaxDelay
+	if retry < 16 {
+		delay = min(retryBaseDelay<<(retry-1), retryMaxDelay)
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// execute runs an SDK helper command within the limits of the provider and repeats it with backoff while
+// it fails with a retryable error. A Retry-After sent along with the error is honoured as long as the wait
+// ends before the deadline of ctx, otherwise the error is returned right away.
+func execute[T any](ctx context.Context, meta *providerMeta, mode retryMode, call func() (T, error)) (T, error) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		release, err := meta.limiter.acquire(ctx)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		resp, err := call()
+		release()
+		err = withCredentialSource(err, meta.credentialSource)
+		if err == nil || attempt >= meta.retry.maxAttempts || !isRetryable(err, mode) {
+			return resp, err
+		}
+		delay := max(retryDelay(attempt), meta.retryAfter.wait())
+		if time.Since(start)+delay > meta.retry.maxElapsed {
+			return resp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return resp, err
+		}
+		log.Printf("[WARN] API call failed with a retryable error, retrying in %s (attempt %d of %d): %v", delay, attempt+1, meta.retry.maxAttempts, err)
+		select {
+		case <-ctx.Done():
+			return resp, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// executeNoContent is execute for commands that only return an error.
+func executeNoContent(ctx context.Context, meta *providerMeta, mode retryMode, call func() error) error {
+	_, err := execute(ctx, meta, mode, func() (struct{}, error) {
+		return struct{}{}, call()
+	})
+	return err
+}
+
+// retryAfterRecorder keeps the latest Retry-After the API sent to a provider configuration along with a
+// 429 or 503 response. The SDK helper doesn't expose response headers in its errors, so they are picked up
+// in the HTTP transport.
+type retryAfterRecorder struct {
+	base http.RoundTripper
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+func (r *retryAfterRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err == nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			r.mu.Lock()
+			if until := time.Now().Add(delay); until.After(r.until) {
+				r.until = until
+			}
+			r.mu.Unlock()
+		}
+	}
+	return resp, err
+}
+
+// wait returns how long the API asked to wait before the next request.
+func (r *retryAfterRecorder) wait() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return max(time.Until(r.until), 0)
+}
+
+// parseRetryAfter reads a Retry-After header in either the delay-seconds or the HTTP-date format.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,168 @@
+package pnap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	bmcapiclient "github.com/phoenixnap/go-sdk-bmc/bmcapi/v3"
+)
+
+func TestUnitPnapSshKey_retry(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ssh_key"),
+		Steps: []resource.TestStep{
+			{
+				// throttling is retried for every call, gateway errors only for reads
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.failures = []mockFailure{
+						{method: http.MethodPost, status: http.StatusTooManyRequests, retryAfter: "1"},
+						{method: http.MethodGet, status: http.StatusServiceUnavailable},
+						{method: http.MethodGet, status: http.StatusBadGateway},
+					}
+				},
+				Config: testUnitProviderConfig(api) + testUnitSshKeyResource(rName, rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("pnap_ssh_key."+rName, "name", rName),
+					testUnitCheckNoPendingFailures(api),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPnapSshKey_noRetryOfUnsafeCalls(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				// the key might have been created before the gateway failed
+				PreConfig: func() {
+					api.mu.Lock()
+					defer api.mu.Unlock()
+					api.failures = []mockFailure{{method: http.MethodPost, status: http.StatusServiceUnavailable}}
+				},
+				Config:      testUnitProviderConfig(api) + testUnitSshKeyResource(rName, rName, false),
+				ExpectError: regexp.MustCompile("503"),
+			},
+		},
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, v := range []struct {
+		err                    error
+		idempotent, throttling bool
+	}{
+		{testAPIError(t, http.StatusTooManyRequests), true, true},
+		{testAPIError(t, http.StatusServiceUnavailable), true, false},
+		{testAPIError(t, http.StatusBadGateway), true, false},
+		{testAPIError(t, http.StatusInternalServerError), false, false},
+		{testAPIError(t, http.StatusNotFound), false, false},
+		{fmt.Errorf("read tcp: %w", syscall.ECONNRESET), true, false},
+		{&url.Error{Op: "Post", URL: "https://api.phoenixnap.com/bmc/v1/servers", Err: io.ErrUnexpectedEOF}, true, false},
+		{errors.New("API Returned Code: 429, Message: Too Many Requests"), false, false},
+		{errors.New("hostname is invalid"), false, false},
+	} {
+		if retryable := isRetryable(v.err, retryIdempotent); retryable != v.idempotent {
+			t.Errorf("isRetryable(%q, retryIdempotent) = %t, expected %t", v.err, retryable, v.idempotent)
+		}
+		if retryable := isRetryable(v.err, retryThrottledOnly); retryable != v.throttling {
+			t.Errorf("isRetryable(%q, retryThrottledOnly) = %t, expected %t", v.err, retryable, v.throttling)
+		}
+	}
+}
+
+func TestExecuteRetryAfter(t *testing.T) {
+	throttled := testAPIError(t, http.StatusTooManyRequests)
+	meta := &providerMeta{retry: retryPolicy{maxAttempts: 5, maxElapsed: time.Hour}, retryAfter: newRetryAfterRecorder()}
+	meta.retryAfter.until = time.Now().Add(2 * time.Minute)
+	if wait := meta.retryAfter.wait(); wait <= retryMaxDelay {
+		t.Errorf("retryAfter.wait() = %s, expected the Retry-After of 2m", wait)
+	}
+
+	// the API asks to wait past the deadline, the error is returned right away
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	calls := 0
+	start := time.Now()
+	_, err := execute(ctx, meta, retryIdempotent, func() (struct{}, error) {
+		calls++
+		return struct{}{}, throttled
+	})
+	if calls != 1 || statusCode(err) != http.StatusTooManyRequests || time.Since(start) > time.Second {
+		t.Errorf("execute() called the API %d times and returned %v after %s, expected a single call", calls, err, time.Since(start))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for value, expected := range map[string]time.Duration{
+		"3":                             3 * time.Second,
+		"Wed, 01 May 2024 12:00:10 GMT": 10 * time.Second,
+		"Wed, 01 May 2024 11:00:00 GMT": 0,
+	} {
+		if delay, ok := parseRetryAfter(value, now); !ok || delay != expected {
+			t.Errorf("parseRetryAfter(%q) = %s, %t, expected %s", value, delay, ok, expected)
+		}
+	}
+	for _, value := range []string{"", "-1", "soon"} {
+		if _, ok := parseRetryAfter(value, now); ok {
+			t.Errorf("parseRetryAfter(%q) accepted an invalid value", value)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for retry, limit := range map[int]time.Duration{1: retryBaseDelay, 3: 4 * retryBaseDelay, 10: retryMaxDelay, 100: retryMaxDelay} {
+		if delay := retryDelay(retry); delay < limit/2 || delay > limit {
+			t.Errorf("retryDelay(%d) = %s, expected between %s and %s", retry, delay, limit/2, limit)
+		}
+	}
+}
+
+// testUnitCheckNoPendingFailures verifies every failure injected into the fake API has been returned.
+func testUnitCheckNoPendingFailures(m *mockAPI) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if len(m.failures) > 0 {
+			return fmt.Errorf("%d injected failures haven't been returned: %v", len(m.failures), m.failures)
+		}
+		return nil
+	}
+}
+
+// testAPIError returns the error the SDK builds out of a response with the given status.
+func testAPIError(t *testing.T, status int) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	configuration := bmcapiclient.NewConfiguration()
+	configuration.Servers = bmcapiclient.ServerConfigurations{{URL: server.URL}}
+	_, _, err := bmcapiclient.NewAPIClient(configuration).SSHKeysAPI.SshKeysGet(context.Background()).Execute()
+	if err == nil {
+		t.Fatalf("the SDK accepted a %d response", status)
+	}
+	return err
+}
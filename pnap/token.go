@@ -0,0 +1,229 @@
+package pnap
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// tokenExpiryMargin renews a token shortly before it expires, so that it doesn't expire in flight.
+	tokenExpiryMargin   = 30 * time.Second
+	tokenCommandTimeout = 1 * time.Minute
+)
+
+// bearerToken is the token API requests of a provider configuration are authenticated with.
+type bearerToken interface {
+	// get returns the current token.
+	get(ctx context.Context) (string, error)
+	// refresh replaces the token the API rejected, it returns the rejected token if it can't be renewed.
+	refresh(ctx context.Context, rejected string) (string, error)
+}
+
+// clientCredentialsToken is obtained from the token URL with the client credentials flow and reused until
+// it expires.
+type clientCredentialsToken struct {
+	source oauth2.TokenSource
+}
+
+// newClientCredentialsToken requests tokens with client, so that they are logged and throttled the same way
+// as the API requests.
+func newClientCredentialsToken(clientID, clientSecret, tokenURL string, client *http.Client) *clientCredentialsToken {
+	config := clientcredentials.Config{ClientID: clientID, ClientSecret: clientSecret, TokenURL: tokenURL}
+	return &clientCredentialsToken{source: config.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, client))}
+}
+
+func (t *clientCredentialsToken) get(ctx context.Context) (string, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// refresh returns the rejected token, the credentials are the same until the configuration changes.
+func (t *clientCredentialsToken) refresh(ctx context.Context, rejected string) (string, error) {
+	return rejected, nil
+}
+
+// externalToken is a bearer token obtained outside of the client credentials flow, either set as
+// access_token or printed by token_command.
+type externalToken struct {
+	// command is run to obtain a token, empty for a static access_token
+	command string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func newStaticToken(token string) *externalToken {
+	return &externalToken{token: token, expiry: jwtExpiry(token)}
+}
+
+func newCommandToken(command string) *externalToken {
+	return &externalToken{command: command}
+}
+
+// get returns the cached token, running token_command again once the token is about to expire.
+func (t *externalToken) get(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.command == "" || (t.token != "" && (t.expiry.IsZero() || time.Until(t.expiry) > tokenExpiryMargin)) {
+		return t.token, nil
+	}
+	return t.refreshLocked(ctx)
+}
+
+// refresh replaces the token the API rejected. A static access_token can't be renewed, it is returned as is.
+func (t *externalToken) refresh(ctx context.Context, rejected string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.command == "" || t.token != rejected {
+		return t.token, nil
+	}
+	return t.refreshLocked(ctx)
+}
+
+func (t *externalToken) refreshLocked(ctx context.Context) (string, error) {
+	token, expiry, err := runTokenCommand(ctx, t.command)
+	if err != nil {
+		return "", err
+	}
+	t.token, t.expiry = token, expiry
+	return token, nil
+}
+
+// runTokenCommand runs token_command in the shell. The command prints either the token, or a JSON object
+// with access_token along with expires_in (in seconds) or expires_at (RFC 3339).
+func runTokenCommand(ctx context.Context, command string) (string, time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, tokenCommandTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", time.Time{}, fmt.Errorf("running token_command: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	output := strings.TrimSpace(stdout.String())
+	if strings.HasPrefix(output, "{") {
+		response := struct {
+			AccessToken string    `json:"access_token"`
+			ExpiresIn   int64     `json:"expires_in"`
+			ExpiresAt   time.Time `json:"expires_at"`
+		}{}
+		if err := json.Unmarshal([]byte(output), &response); err != nil {
+			return "", time.Time{}, fmt.Errorf("parsing the output of token_command: %w", err)
+		}
+		output = response.AccessToken
+		if response.ExpiresIn > 0 {
+			return output, time.Now().Add(time.Duration(response.ExpiresIn) * time.Second), nil
+		}
+		if !response.ExpiresAt.IsZero() {
+			return output, response.ExpiresAt, nil
+		}
+	}
+	if output == "" {
+		return "", time.Time{}, fmt.Errorf("token_command didn't print a token")
+	}
+	return output, jwtExpiry(output), nil
+}
+
+// jwtExpiry returns the expiry of a JWT, zero if the token isn't one or doesn't expire.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// authTransport authenticates the API requests of a provider configuration with its token. A request
+// rejected with 401 is sent once more if the token can be renewed.
+type authTransport struct {
+	base  http.RoundTripper
+	token bearerToken
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	current, err := t.token.get(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(withBearer(req, current))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+	renewed, err := t.token.refresh(req.Context(), current)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if renewed == current {
+		return resp, nil
+	}
+	retry := withBearer(req, renewed)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// withBearer returns a copy of the request authenticated with the given token.
+func withBearer(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "Bearer "+token)
+	return clone
+}
+
+// tokenEndpointResponse answers the client credentials request of the SDK helper with the handle taken from
+// the token URL. The handle never expires, the token behind it is renewed by authTransport.
+func tokenEndpointResponse(req *http.Request) *http.Response {
+	body, _ := json.Marshal(map[string]interface{}{
+		"access_token": strings.TrimPrefix(req.URL.Path, "/"),
+		"token_type":   "Bearer",
+	})
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
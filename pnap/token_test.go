@@ -0,0 +1,128 @@
+package pnap
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestUnitPnapProvider_accessToken(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ssh_key"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderTokenConfig(api, fmt.Sprintf("access_token = %q", mockAccessToken)) +
+					testUnitSshKeyResource(rName, rName, false),
+				Check: resource.TestCheckResourceAttr("pnap_ssh_key."+rName, "name", rName),
+			},
+		},
+	})
+}
+
+func TestUnitPnapProvider_rejectedAccessToken(t *testing.T) {
+	api := newMockAPI(t)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config:      testUnitProviderTokenConfig(api, `access_token = "revoked-token"`) + testUnitSshKeyResource("rejected", "rejected", false),
+				ExpectError: regexp.MustCompile(`credentials from access_token`),
+			},
+		},
+	})
+}
+
+func TestUnitPnapProvider_explicitCredentialsOverEnvAccessToken(t *testing.T) {
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	t.Setenv("PNAP_ACCESS_TOKEN", "revoked-token")
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ssh_key"),
+		Steps: []resource.TestStep{
+			{
+				// the token of the environment applies once no credentials are set in the provider block
+				Config:      testUnitProviderTokenConfig(api, "") + testUnitSshKeyResource(rName, rName, false),
+				ExpectError: regexp.MustCompile(`credentials from access_token`),
+			},
+			{
+				// client_id and client_secret of the provider block win over the token of the environment
+				Config: testUnitProviderConfig(api) + testUnitSshKeyResource(rName, rName, false),
+				Check:  resource.TestCheckResourceAttr("pnap_ssh_key."+rName, "name", rName),
+			},
+		},
+	})
+}
+
+func TestUnitPnapProvider_tokenCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the token command is a POSIX shell script")
+	}
+	api := newMockAPI(t)
+	rName := "unittest-" + acctest.RandStringFromCharSet(7, acctest.CharSetAlphaNum)
+	// the first token is rejected by the API, the provider has to run the command again mid-apply
+	marker := filepath.Join(t.TempDir(), "issued")
+	command := fmt.Sprintf(`if [ -f %s ]; then echo %s; else touch %s; echo stale-token; fi`, marker, mockAccessToken, marker)
+	resource.UnitTest(t, resource.TestCase{
+		ProviderFactories: testUnitProviderFactories(),
+		CheckDestroy:      testUnitCheckDestroy(api, "pnap_ssh_key"),
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitProviderTokenConfig(api, fmt.Sprintf("token_command = %q", command)) +
+					testUnitSshKeyResource(rName, rName, false),
+				Check: resource.TestCheckResourceAttr("pnap_ssh_key."+rName, "name", rName),
+			},
+		},
+	})
+}
+
+func TestRunTokenCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the token commands are POSIX shell commands")
+	}
+	token, expiry, err := runTokenCommand(context.Background(), `echo '{"access_token": "json-token", "expires_in": 600}'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "json-token" || time.Until(expiry) < 590*time.Second || time.Until(expiry) > 600*time.Second {
+		t.Errorf("runTokenCommand() = %q, %s, expected json-token expiring in 600s", token, expiry)
+	}
+	if token, expiry, err = runTokenCommand(context.Background(), "echo plain-token"); err != nil || token != "plain-token" || !expiry.IsZero() {
+		t.Errorf("runTokenCommand() = %q, %s, %v, expected plain-token without expiry", token, expiry, err)
+	}
+	if _, _, err = runTokenCommand(context.Background(), "echo broker unavailable >&2; exit 1"); err == nil || !regexp.MustCompile("broker unavailable").MatchString(err.Error()) {
+		t.Errorf("runTokenCommand() returned %v, expected the output of the failing command", err)
+	}
+}
+
+func TestJwtExpiry(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"ci","exp":1700000000}`))
+	if expiry := jwtExpiry("eyJhbGciOiJSUzI1NiJ9." + payload + ".signature"); !expiry.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("jwtExpiry() = %s, expected %s", expiry, time.Unix(1700000000, 0))
+	}
+	if expiry := jwtExpiry("opaque-token"); !expiry.IsZero() {
+		t.Errorf("jwtExpiry() of an opaque token = %s, expected zero", expiry)
+	}
+}
+
+// testUnitProviderTokenConfig returns a provider block authenticating with the given token setting against the fake API.
+func testUnitProviderTokenConfig(m *mockAPI, token string) string {
+	return fmt.Sprintf(`
+provider "pnap" {
+	%s
+	api_base_url = "%s/"
+	poll_interval = 1
+}
+`, token, m.URL)
+}
@@ -0,0 +1,92 @@
+package pnap
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// handleTokenHost serves the token endpoint the SDK helper is configured with, it is answered by
+// clientRouter and never reaches the network.
+const handleTokenHost = "terraform-provider-pnap.invalid"
+
+// baseTransport sends the requests of every provider configuration to the network.
+var baseTransport = http.DefaultTransport
+
+// newProviderHTTPClient returns the HTTP client of a provider configuration. Its requests are authenticated
+// with token, the Retry-After of throttled responses is kept by retryAfter and requests are logged at
+// debug level.
+func newProviderHTTPClient(token bearerToken, retryAfter *retryAfterRecorder) *http.Client {
+	return &http.Client{Transport: &authTransport{base: retryAfter, token: token}}
+}
+
+// newRetryAfterRecorder returns the recorder of a provider configuration, which sends its requests to the
+// network through the debug logger.
+func newRetryAfterRecorder() *retryAfterRecorder {
+	return &retryAfterRecorder{base: &httpLogger{base: baseTransport}}
+}
+
+// clientRouter hands the requests of the SDK helper to the HTTP client of the provider configuration that
+// sent them. The SDK helper only sends requests through http.DefaultTransport and can't be given a client,
+// so it is configured with a token URL on handleTokenHost, answered with a handle naming the client of the
+// provider configuration. API requests bearing the handle are sent with that client, which replaces the
+// handle with its token. Every other request goes through unchanged.
+type clientRouter struct {
+	base http.RoundTripper
+
+	mu      sync.Mutex
+	clients map[string]*http.Client
+	// registered counts the clients ever registered, so that handles aren't reused once released
+	registered int
+}
+
+var (
+	providerClients         = &clientRouter{clients: make(map[string]*http.Client)}
+	installClientRouterOnce sync.Once
+)
+
+// installClientRouter wraps http.DefaultTransport once per process, the state of each provider
+// configuration is kept in its own client.
+func installClientRouter() {
+	installClientRouterOnce.Do(func() {
+		providerClients.base = http.DefaultTransport
+		http.DefaultTransport = providerClients
+	})
+}
+
+// register returns the token URL the SDK helper has to be configured with to send its requests with client.
+func (r *clientRouter) register(client *http.Client) string {
+	installClientRouter()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registered++
+	handle := fmt.Sprintf("terraform-provider-pnap-%d", r.registered)
+	r.clients[handle] = client
+	return "https://" + handleTokenHost + "/" + handle
+}
+
+// unregister releases the client registered with tokenURL, the requests bearing its handle go through unchanged
+// afterwards.
+func (r *clientRouter) unregister(tokenURL string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, strings.TrimPrefix(tokenURL, "https://"+handleTokenHost+"/"))
+}
+
+func (r *clientRouter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == handleTokenHost {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return tokenEndpointResponse(req), nil
+	}
+	handle := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	r.mu.Lock()
+	client := r.clients[handle]
+	r.mu.Unlock()
+	if client == nil {
+		return r.base.RoundTrip(req)
+	}
+	return client.Transport.RoundTrip(req)
+}
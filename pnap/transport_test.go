@@ -0,0 +1,92 @@
+package pnap
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientRouter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer staging-token" {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer server.Close()
+
+	production := newRetryAfterRecorder()
+	staging := newRetryAfterRecorder()
+	productionURL := providerClients.register(newProviderHTTPClient(newStaticToken("production-token"), production))
+	stagingURL := providerClients.register(newProviderHTTPClient(newStaticToken("staging-token"), staging))
+	if productionURL == stagingURL {
+		t.Fatalf("both clients were registered as %s", productionURL)
+	}
+
+	// the SDK helper sends its requests through http.DefaultTransport, with the handle as bearer token
+	send := func(tokenURL string) *http.Response {
+		resp, err := http.Post(tokenURL, "application/x-www-form-urlencoded", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		token := struct {
+			AccessToken string `json:"access_token"`
+		}{}
+		json.NewDecoder(resp.Body).Decode(&token)
+		resp.Body.Close()
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/bmc/v1/servers", nil)
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+		if resp, err = http.DefaultClient.Do(req); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := send(productionURL)
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	resp.Body.Close()
+	if string(body[:n]) != "Bearer production-token" {
+		t.Errorf("the request was sent with %q, expected the production token", body[:n])
+	}
+	resp = send(stagingURL)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("the request was answered with %s, expected the staging token to be throttled", resp.Status)
+	}
+	if wait := staging.wait(); wait < 110*time.Second {
+		t.Errorf("staging.wait() = %s, expected the Retry-After of 2m", wait)
+	}
+	if wait := production.wait(); wait != 0 {
+		t.Errorf("production.wait() = %s, expected the Retry-After of staging not to apply", wait)
+	}
+
+	// requests that weren't sent by the SDK helper go through unchanged
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Authorization", "Bearer other-token")
+	if resp, err := http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	} else {
+		n, _ := resp.Body.Read(body)
+		resp.Body.Close()
+		if string(body[:n]) != "Bearer other-token" {
+			t.Errorf("the request was sent with %q, expected its own token", body[:n])
+		}
+	}
+
+	// once the provider stops, its handle is no longer replaced with its token
+	providerClients.unregister(productionURL)
+	resp = send(productionURL)
+	n, _ = resp.Body.Read(body)
+	resp.Body.Close()
+	if string(body[:n]) == "Bearer production-token" {
+		t.Errorf("the request was sent with the production token of an unregistered client")
+	}
+	if url := providerClients.register(newProviderHTTPClient(newStaticToken("other-token"), newRetryAfterRecorder())); url == productionURL || url == stagingURL {
+		t.Errorf("the handle %s was registered again", url)
+	}
+}